@@ -12,6 +12,8 @@ import (
 type NewStorageRequest struct {
 	Source string `json:"source"`
 	Type   string `json:"type"`
+	// LUKS is required for LUKSPart storage
+	LUKS *service.LUKSStorageOption `json:"luks"`
 }
 
 var newStorage haruka.RequestHandler = func(context *haruka.Context) {
@@ -21,6 +23,22 @@ var newStorage haruka.RequestHandler = func(context *haruka.Context) {
 		AbortErrorWithStatus(err, context, http.StatusBadRequest)
 		return
 	}
+	if body.Type == "LUKSPart" {
+		if body.LUKS == nil {
+			AbortErrorWithStatus(service.InvalidKeyError, context, http.StatusBadRequest)
+			return
+		}
+		body.LUKS.Source = body.Source
+		err = service.DefaultStoragePool.NewLUKSStorage(*body.LUKS)
+		if err != nil {
+			AbortErrorWithStatus(err, context, luksErrorStatus(err))
+			return
+		}
+		context.JSON(haruka.JSON{
+			"success": true,
+		})
+		return
+	}
 	err = service.DefaultStoragePool.NewStorage(body.Source, body.Type)
 	if err != nil {
 		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
@@ -33,7 +51,7 @@ var newStorage haruka.RequestHandler = func(context *haruka.Context) {
 
 var getStorageListHandler haruka.RequestHandler = func(context *haruka.Context) {
 	data := make([]*StorageTemplate, 0)
-	for _, storage := range service.DefaultStoragePool.Storages {
+	for _, storage := range service.DefaultStoragePool.List() {
 		template := &StorageTemplate{}
 		template.Assign(storage)
 		data = append(data, template)
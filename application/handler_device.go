@@ -10,7 +10,7 @@ var deviceInfoHandler haruka.RequestHandler = func(context *haruka.Context) {
 	ZFSCount, _ := service.DefaultZFSManager.GetPoolCount()
 	shareFolderCount, _ := service.GetShareFolderCount()
 	appCount := len(service.DefaultAppManager.Apps)
-	storageCount := len(service.DefaultStoragePool.Storages)
+	storageCount := len(service.DefaultStoragePool.List())
 	diskCount := len(service.ReadDiskList())
 	context.JSON(haruka.JSON{
 		"success":          true,
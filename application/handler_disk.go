@@ -2,13 +2,15 @@ package application
 
 import (
 	"errors"
+	"net/http"
+
 	"github.com/allentom/haruka"
 	"github.com/projectxpolaris/youplus/service"
 	"github.com/projectxpolaris/youplus/utils"
 )
 
 var getDiskListHandler haruka.RequestHandler = func(context *haruka.Context) {
-	disks := service.ReadDiskList()
+	disks := service.DefaultDiskInventory.GetDisks()
 	context.JSON(haruka.JSON{
 		"disks": disks,
 	})
@@ -100,3 +102,246 @@ var removePartitionHandler haruka.RequestHandler = func(context *haruka.Context)
 		"message": "success",
 	})
 }
+
+func partitionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.DiskNotFoundError), errors.Is(err, service.PartitionNotFoundError):
+		return http.StatusNotFound
+	case errors.Is(err, service.DiskInUseError):
+		return http.StatusConflict
+	case errors.Is(err, service.NoPartitionTableError),
+		errors.Is(err, service.UnsupportedTableLabelError),
+		errors.Is(err, service.UnsupportedFormatError),
+		errors.Is(err, service.UnsupportedResizeError),
+		errors.Is(err, service.InvalidPartitionOptionError):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func newPartitionCallback() service.PartitionCallback {
+	return service.PartitionCallback{
+		OnDone: func(task *service.PartitionTask) {
+			sendTaskNotification(PartitionDoneEvent, task)
+		},
+		OnError: func(task *service.PartitionTask) {
+			sendTaskNotification(PartitionErrorEvent, task)
+		},
+	}
+}
+
+var createPartitionTableHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.PartitionTableOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	task, err := service.DefaultTaskPool.NewPartitionTableTask(body, newPartitionCallback())
+	if err != nil {
+		AbortErrorWithStatus(err, context, partitionErrorStatus(err))
+		return
+	}
+	template := TaskTemplate{}
+	template.Assign(task)
+	context.JSON(template)
+}
+
+var createPartitionHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.CreatePartitionOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	task, err := service.DefaultTaskPool.NewCreatePartitionTask(body, newPartitionCallback())
+	if err != nil {
+		AbortErrorWithStatus(err, context, partitionErrorStatus(err))
+		return
+	}
+	template := TaskTemplate{}
+	template.Assign(task)
+	context.JSON(template)
+}
+
+var formatPartitionHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.FormatPartitionOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	task, err := service.DefaultTaskPool.NewFormatPartitionTask(body, newPartitionCallback())
+	if err != nil {
+		AbortErrorWithStatus(err, context, partitionErrorStatus(err))
+		return
+	}
+	template := TaskTemplate{}
+	template.Assign(task)
+	context.JSON(template)
+}
+
+var resizePartitionHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.ResizePartitionOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	task, err := service.DefaultTaskPool.NewResizePartitionTask(body, newPartitionCallback())
+	if err != nil {
+		AbortErrorWithStatus(err, context, partitionErrorStatus(err))
+		return
+	}
+	template := TaskTemplate{}
+	template.Assign(task)
+	context.JSON(template)
+}
+
+var diskSmartHistoryHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetQueryString("name")
+	since, _ := context.GetQueryInt("since")
+	samples, err := service.GetSmartHistory(name, int64(since))
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	data := make([]SmartSampleTemplate, 0)
+	for idx := range samples {
+		template := SmartSampleTemplate{}
+		template.Assign(&samples[idx])
+		data = append(data, template)
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    data,
+	})
+}
+
+var diskSelfTestListHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetQueryString("name")
+	results, err := service.GetSelfTestResults(name)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	data := make([]SmartTestResultTemplate, 0)
+	for idx := range results {
+		template := SmartTestResultTemplate{}
+		template.Assign(&results[idx])
+		data = append(data, template)
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    data,
+	})
+}
+
+type StartSelfTestRequestBody struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+var startDiskSelfTestHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body StartSelfTestRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.StartSelfTest(body.Name, body.Type)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.DiskNotFoundError) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, service.UnsupportedSelfTestError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var migrateFstabHandler haruka.RequestHandler = func(context *haruka.Context) {
+	migrations, err := service.MigrateFstabToStableSpec()
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    migrations,
+	})
+}
+
+var getDiskPowerHandler haruka.RequestHandler = func(context *haruka.Context) {
+	statuses, err := service.GetDiskPowerStatus()
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	data := make([]DiskPowerTemplate, 0)
+	for idx := range statuses {
+		template := DiskPowerTemplate{}
+		template.Assign(&statuses[idx])
+		data = append(data, template)
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    data,
+	})
+}
+
+var setDiskPowerHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.DiskPowerOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	_, err = service.SetDiskPowerSetting(body)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.DiskNotFoundError) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, service.InvalidPowerSettingError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var secureWipeDiskHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.WipeDiskOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	task, err := service.DefaultTaskPool.NewWipeDiskTask(body, service.WipeCallback{
+		OnDone: func(task *service.WipeTask) {
+			sendTaskNotification(WipeDoneEvent, task)
+		},
+		OnError: func(task *service.WipeTask) {
+			sendTaskNotification(WipeErrorEvent, task)
+		},
+	})
+	if err != nil {
+		status := partitionErrorStatus(err)
+		if errors.Is(err, service.UnsupportedWipeMethodError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
+		return
+	}
+	template := TaskTemplate{}
+	template.Assign(task)
+	context.JSON(template)
+}
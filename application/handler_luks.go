@@ -0,0 +1,175 @@
+package application
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/allentom/haruka"
+	"github.com/projectxpolaris/youplus/service"
+)
+
+func luksErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.StorageNotFoundError), errors.Is(err, service.PartitionNotFoundError),
+		errors.Is(err, service.KeySlotNotFoundError):
+		return http.StatusNotFound
+	case errors.Is(err, service.DiskInUseError), errors.Is(err, service.StorageAlreadyOpenError),
+		errors.Is(err, service.LastKeySlotError):
+		return http.StatusConflict
+	case errors.Is(err, service.InvalidKeyError), errors.Is(err, service.StorageNotLUKSError),
+		errors.Is(err, service.InvalidPartitionOptionError):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+var unlockStorageHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.LUKSKey
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	storage, err := service.GetLUKSStorage(context.GetPathParameterAsString("id"))
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	err = storage.Unlock(body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var lockStorageHandler haruka.RequestHandler = func(context *haruka.Context) {
+	storage, err := service.GetLUKSStorage(context.GetPathParameterAsString("id"))
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	err = storage.Lock()
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var getStorageKeySlotsHandler haruka.RequestHandler = func(context *haruka.Context) {
+	storage, err := service.GetLUKSStorage(context.GetPathParameterAsString("id"))
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	slots, err := storage.ListKeySlots()
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    slots,
+	})
+}
+
+type AddKeySlotRequest struct {
+	service.LUKSKey
+	NewKey service.LUKSKey `json:"newKey"`
+}
+
+var addStorageKeySlotHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body AddKeySlotRequest
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	storage, err := service.GetLUKSStorage(context.GetPathParameterAsString("id"))
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	err = storage.AddKey(body.LUKSKey, body.NewKey)
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var removeStorageKeySlotHandler haruka.RequestHandler = func(context *haruka.Context) {
+	slot, err := context.GetPathParameterAsInt("slot")
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	var body service.LUKSKey
+	err = context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	storage, err := service.GetLUKSStorage(context.GetPathParameterAsString("id"))
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	err = storage.RemoveKey(slot, body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+type StorageAutoUnlockRequest struct {
+	KeyFile string `json:"keyFile"`
+}
+
+var setStorageAutoUnlockHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body StorageAutoUnlockRequest
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	storage, err := service.GetLUKSStorage(context.GetPathParameterAsString("id"))
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	err = storage.SetAutoUnlock(body.KeyFile)
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var removeStorageAutoUnlockHandler haruka.RequestHandler = func(context *haruka.Context) {
+	storage, err := service.GetLUKSStorage(context.GetPathParameterAsString("id"))
+	if err != nil {
+		AbortErrorWithStatus(err, context, luksErrorStatus(err))
+		return
+	}
+	err = storage.RemoveAutoUnlock()
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
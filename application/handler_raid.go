@@ -0,0 +1,125 @@
+package application
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/allentom/haruka"
+	"github.com/projectxpolaris/youplus/service"
+)
+
+func raidErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.MDArrayNotFoundError), errors.Is(err, service.MDMemberNotFoundError),
+		errors.Is(err, service.DiskNotFoundError):
+		return http.StatusNotFound
+	case errors.Is(err, service.DiskInUseError), errors.Is(err, service.MDArrayRedundancyError):
+		return http.StatusConflict
+	case errors.Is(err, service.InvalidMDArrayOptionError), errors.Is(err, service.UnsupportedFormatError),
+		errors.Is(err, service.InvalidPartitionOptionError):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+var getRaidListHandler haruka.RequestHandler = func(context *haruka.Context) {
+	arrays, err := service.GetMDArrays()
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    arrays,
+	})
+}
+
+var getRaidHandler haruka.RequestHandler = func(context *haruka.Context) {
+	array, err := service.GetMDArray(context.GetPathParameterAsString("name"))
+	if err != nil {
+		AbortErrorWithStatus(err, context, raidErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    array,
+	})
+}
+
+var createRaidHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.MDArrayOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	array, err := service.CreateMDArray(body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, raidErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    array,
+	})
+}
+
+var removeRaidHandler haruka.RequestHandler = func(context *haruka.Context) {
+	err := service.DeleteMDArray(context.GetPathParameterAsString("name"))
+	if err != nil {
+		AbortErrorWithStatus(err, context, raidErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+type RaidMemberRequest struct {
+	Device string `json:"device"`
+}
+
+var addRaidMemberHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body RaidMemberRequest
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.AddMDMember(context.GetPathParameterAsString("name"), body.Device)
+	if err != nil {
+		AbortErrorWithStatus(err, context, raidErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var removeRaidMemberHandler haruka.RequestHandler = func(context *haruka.Context) {
+	err := service.RemoveMDMember(context.GetPathParameterAsString("name"), context.GetPathParameterAsString("device"))
+	if err != nil {
+		AbortErrorWithStatus(err, context, raidErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var formatRaidHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.FormatMDArrayOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	task, err := service.DefaultTaskPool.NewFormatMDArrayTask(context.GetPathParameterAsString("name"), body, newPartitionCallback())
+	if err != nil {
+		AbortErrorWithStatus(err, context, raidErrorStatus(err))
+		return
+	}
+	template := TaskTemplate{}
+	template.Assign(task)
+	context.JSON(template)
+}
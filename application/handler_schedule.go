@@ -0,0 +1,114 @@
+package application
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/allentom/haruka"
+	"github.com/projectxpolaris/youplus/service"
+)
+
+func scheduleErrorStatus(err error) int {
+	if errors.Is(err, service.UnsupportedJobTypeError) || errors.Is(err, service.InvalidJobIntervalError) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, service.JobNotFoundError) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+var getScheduledJobListHandler haruka.RequestHandler = func(context *haruka.Context) {
+	jobs, err := service.GetScheduledJobs(context.GetQueryString("type"))
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	data := make([]ScheduledJobTemplate, 0)
+	for idx := range jobs {
+		template := ScheduledJobTemplate{}
+		template.Assign(&jobs[idx])
+		data = append(data, template)
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    data,
+	})
+}
+
+var createScheduledJobHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.ScheduledJobOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	job, err := service.CreateScheduledJob(body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, scheduleErrorStatus(err))
+		return
+	}
+	template := ScheduledJobTemplate{}
+	template.Assign(job)
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    template,
+	})
+}
+
+var updateScheduledJobHandler haruka.RequestHandler = func(context *haruka.Context) {
+	id, err := context.GetPathParameterAsInt("id")
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	var body service.ScheduledJobOption
+	err = context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	job, err := service.UpdateScheduledJob(uint(id), body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, scheduleErrorStatus(err))
+		return
+	}
+	template := ScheduledJobTemplate{}
+	template.Assign(job)
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    template,
+	})
+}
+
+var deleteScheduledJobHandler haruka.RequestHandler = func(context *haruka.Context) {
+	id, err := context.GetPathParameterAsInt("id")
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DeleteScheduledJob(uint(id))
+	if err != nil {
+		AbortErrorWithStatus(err, context, scheduleErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var runScheduledJobHandler haruka.RequestHandler = func(context *haruka.Context) {
+	id, err := context.GetPathParameterAsInt("id")
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultScheduler.RunJobNow(uint(id))
+	if err != nil {
+		AbortErrorWithStatus(err, context, scheduleErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
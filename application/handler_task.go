@@ -1,6 +1,9 @@
 package application
 
 import (
+	"errors"
+	"net/http"
+
 	"github.com/allentom/haruka"
 	"github.com/projectxpolaris/youplus/service"
 )
@@ -16,3 +19,20 @@ var tasksListHandler haruka.RequestHandler = func(context *haruka.Context) {
 		"tasks": templates,
 	})
 }
+
+var cancelTaskHandler haruka.RequestHandler = func(context *haruka.Context) {
+	err := service.DefaultTaskPool.CancelTask(context.GetPathParameterAsString("id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.TaskNotFoundError) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, service.TaskNotCancelableError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
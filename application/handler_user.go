@@ -290,6 +290,9 @@ var getUserShareFolderListHandler haruka.RequestHandler = func(context *haruka.C
 		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
 		return
 	}
+	for _, folder := range userShareFolder {
+		folder.Quota = service.GetUserShareQuota(folder.Folder.Path, user.Username)
+	}
 	context.JSON(haruka.JSON{
 		"success": true,
 		"folders": userShareFolder,
@@ -45,11 +45,28 @@ var createZFSPoolWithNodeHandler haruka.RequestHandler = func(context *haruka.Co
 	}
 	err = service.DefaultZFSManager.CreatePoolWithNode(body.Name, body.Conf)
 	if err != nil {
-		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.InvalidPoolLayoutError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var previewZFSPoolLayoutHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body CreateZFSPoolWithConfRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
 		return
 	}
 	context.JSON(haruka.JSON{
 		"success": true,
+		"data":    service.PreviewPoolLayout(body.Conf),
 	})
 }
 
@@ -90,6 +107,9 @@ var getZFSPoolHandler haruka.RequestHandler = func(context *haruka.Context) {
 	template := &ZFSPoolTemplate{}
 	template.Assign(*pool)
 	pool.Close()
+	if trim, err := service.DefaultZFSManager.GetTrimStatus(name); err == nil {
+		template.Trim = trim
+	}
 	context.JSON(haruka.JSON{
 		"data":    template,
 		"success": "true",
@@ -128,7 +148,9 @@ var datasetListHandler haruka.RequestHandler = func(context *haruka.Context) {
 }
 
 type CreateDatasetRequestBody struct {
-	Path string `json:"path"`
+	Path       string                           `json:"path"`
+	Props      map[string]string                `json:"props"`
+	Encryption *service.DatasetEncryptionOption `json:"encryption"`
 }
 
 var createDatasetHandler haruka.RequestHandler = func(context *haruka.Context) {
@@ -138,9 +160,20 @@ var createDatasetHandler haruka.RequestHandler = func(context *haruka.Context) {
 		AbortErrorWithStatus(err, context, http.StatusBadRequest)
 		return
 	}
-	dataset, err := service.DefaultZFSManager.CreateDataset(body.Path)
+	var dataset zfs.Dataset
+	if body.Encryption != nil {
+		dataset, err = service.DefaultZFSManager.CreateEncryptedDataset(body.Path, body.Props, *body.Encryption)
+	} else {
+		dataset, err = service.DefaultZFSManager.CreateDataset(body.Path, body.Props)
+	}
 	if err != nil {
-		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.UnsupportedPropertyError) ||
+			errors.Is(err, service.InvalidPropertyValueError) ||
+			errors.Is(err, service.InvalidKeyError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
 		return
 	}
 	template := DatasetTemplate{}
@@ -151,6 +184,54 @@ var createDatasetHandler haruka.RequestHandler = func(context *haruka.Context) {
 	})
 }
 
+var getDatasetPropertiesHandler haruka.RequestHandler = func(context *haruka.Context) {
+	datasetPath := context.GetQueryString("path")
+	props, err := service.DefaultZFSManager.GetDatasetProperties(datasetPath)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"props":   props,
+	})
+}
+
+type UpdateDatasetPropertiesRequestBody struct {
+	Path  string            `json:"path"`
+	Props map[string]string `json:"props"`
+}
+
+var updateDatasetPropertiesHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body UpdateDatasetPropertiesRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.SetDatasetProperties(body.Path, body.Props)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var inheritDatasetPropertyHandler haruka.RequestHandler = func(context *haruka.Context) {
+	datasetPath := context.GetQueryString("path")
+	name := context.GetQueryString("name")
+	err := service.DefaultZFSManager.InheritDatasetProperty(datasetPath, name)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
 var deleteDatasetHandler haruka.RequestHandler = func(context *haruka.Context) {
 	datasetPath := context.GetQueryString("path")
 	err := service.DefaultZFSManager.DeleteDataset(datasetPath)
@@ -225,12 +306,123 @@ var datasetSnapshotRollbackHandler haruka.RequestHandler = func(context *haruka.
 	})
 }
 
+type SnapshotDiffQuery struct {
+	Dataset  string `hsource:"query" hname:"dataset"`
+	From     string `hsource:"query" hname:"from"`
+	To       string `hsource:"query" hname:"to"`
+	Page     int    `hsource:"query" hname:"page"`
+	PageSize int    `hsource:"query" hname:"pageSize"`
+}
+
+var snapshotDiffHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var query SnapshotDiffQuery
+	err := context.BindingInput(&query)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	entries, total, err := service.DefaultZFSManager.GetSnapshotDiff(service.SnapshotDiffOption{
+		Dataset:  query.Dataset,
+		From:     query.From,
+		To:       query.To,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	})
+	if err != nil {
+		AbortErrorWithStatus(err, context, snapshotFileErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"list":    entries,
+		"total":   total,
+	})
+}
+
+func snapshotFileErrorStatus(err error) int {
+	if errors.Is(err, service.NotZFSPathError) ||
+		errors.Is(err, service.InvalidSnapshotPathError) ||
+		errors.Is(err, service.InvalidRestoreTargetError) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, service.SnapshotNotFoundError) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func cloneErrorStatus(err error) int {
+	if errors.Is(err, service.SnapshotNotFoundError) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, service.ShareExistsError) {
+		return http.StatusConflict
+	}
+	if errors.Is(err, service.UnsupportedPropertyError) || errors.Is(err, service.InvalidPropertyValueError) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+var snapshotFilesHandler haruka.RequestHandler = func(context *haruka.Context) {
+	path := context.GetQueryString("path")
+	snapshot := context.GetQueryString("snapshot")
+	dir := context.GetQueryString("dir")
+	items, err := service.DefaultZFSManager.ReadSnapshotDir(path, snapshot, dir)
+	if err != nil {
+		AbortErrorWithStatus(err, context, snapshotFileErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"list":    items,
+	})
+}
+
+var restoreSnapshotFilesHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.RestoreSnapshotFilesOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	task, err := service.DefaultTaskPool.NewRestoreSnapshotFilesTask(body, service.RestoreSnapshotFilesCallback{
+		OnDone: func(task *service.RestoreSnapshotFilesTask) {
+			template := TaskTemplate{}
+			template.Assign(task)
+			DefaultNotificationManager.sendJSONToAll(haruka.JSON{
+				"event": RestoreDoneEvent,
+				"data":  template,
+			})
+		},
+		OnError: func(task *service.RestoreSnapshotFilesTask) {
+			template := TaskTemplate{}
+			template.Assign(task)
+			DefaultNotificationManager.sendJSONToAll(haruka.JSON{
+				"event": RestoreErrorEvent,
+				"data":  template,
+			})
+		},
+	})
+	if err != nil {
+		AbortErrorWithStatus(err, context, snapshotFileErrorStatus(err))
+		return
+	}
+	template := TaskTemplate{}
+	template.Assign(task)
+	context.JSON(template)
+}
+
 var deleteSnapshotHandler haruka.RequestHandler = func(context *haruka.Context) {
 	dataset := context.GetQueryString("dataset")
 	snapshot := context.GetQueryString("snapshot")
 	err := service.DefaultZFSManager.DeleteSnapshot(dataset, snapshot)
 	if err != nil {
-		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.SnapshotHasClonesError) || errors.Is(err, service.SnapshotHeldError) {
+			status = http.StatusConflict
+		}
+		AbortErrorWithStatus(err, context, status)
 		return
 	}
 	context.JSON(haruka.JSON{
@@ -250,3 +442,798 @@ var getZFSPoolsMonitorHandler haruka.RequestHandler = func(context *haruka.Conte
 		"pools":   stats,
 	})
 }
+
+var getZFSPoolsIOStatHandler haruka.RequestHandler = func(context *haruka.Context) {
+	pool := context.GetQueryString("pool")
+	resolution := context.GetQueryString("resolution")
+	if len(resolution) == 0 {
+		resolution = "second"
+	}
+	series, err := service.DefaultPoolIOStatMonitor.GetPoolIOStat(pool, resolution)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"series":  series,
+	})
+}
+
+var getZFSARCHandler haruka.RequestHandler = func(context *haruka.Context) {
+	stats, err := service.ReadARCStats()
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	since, err := context.GetQueryInt("since")
+	if err != nil {
+		since = 0
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"stats":   stats,
+		"history": service.DefaultARCMonitor.GetHistory(int64(since)),
+	})
+}
+
+func zfsDeviceErrorStatus(err error) int {
+	if errors.Is(err, service.DiskNotFoundError) || errors.Is(err, service.DiskInUseError) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, service.PoolNotFoundError) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+type ZFSPoolDeviceRequestBody struct {
+	Device    string `json:"device"`
+	NewDisk   string `json:"newDisk"`
+	Force     bool   `json:"force"`
+	Expand    bool   `json:"expand"`
+	Temporary bool   `json:"temporary"`
+}
+
+var replacePoolDeviceHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	var body ZFSPoolDeviceRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.ReplaceDevice(name, body.Device, body.NewDisk, body.Force)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var attachPoolDeviceHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	var body ZFSPoolDeviceRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.AttachDevice(name, body.Device, body.NewDisk, body.Force)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var detachPoolDeviceHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	var body ZFSPoolDeviceRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.DetachDevice(name, body.Device)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var onlinePoolDeviceHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	var body ZFSPoolDeviceRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.OnlineDevice(name, body.Device, body.Expand)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var offlinePoolDeviceHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	var body ZFSPoolDeviceRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.OfflineDevice(name, body.Device, body.Temporary)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+type AddZFSPoolVdevRequestBody struct {
+	Conf  service.Node `json:"conf"`
+	Force bool         `json:"force"`
+}
+
+var addPoolVdevHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	var body AddZFSPoolVdevRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.AddVdevs(name, body.Conf, body.Force)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var getPoolScanStatusHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	status, err := service.DefaultZFSManager.GetPoolScanStatus(name)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    status,
+	})
+}
+
+var getImportablePoolListHandler haruka.RequestHandler = func(context *haruka.Context) {
+	searchPaths := context.Request.URL.Query()["searchPath"]
+	pools, err := service.DefaultZFSManager.GetImportablePools(searchPaths)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	data := make([]ImportablePoolTemplate, 0)
+	for _, pool := range pools {
+		template := ImportablePoolTemplate{}
+		template.Assign(&pool)
+		data = append(data, template)
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"pools":   data,
+	})
+}
+
+var importPoolHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.ImportPoolOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.ImportPool(body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+type ExportPoolRequestBody struct {
+	Force bool `json:"force"`
+}
+
+var exportPoolHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	var body ExportPoolRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.ExportPool(name, body.Force)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var volumeListHandler haruka.RequestHandler = func(context *haruka.Context) {
+	filter := service.DatasetQueryFilter{}
+	err := context.BindingInput(&filter)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	filter.Type = "volume"
+	datasets, err := service.DefaultZFSManager.GetAllDataset(filter)
+	if err != nil {
+		AbortErrorWithStatus(err, context, 500)
+		return
+	}
+	data := SerializerDatasetTemplates(datasets)
+	service.DefaultZFSManager.CloseAllDataset(datasets)
+	context.JSON(haruka.JSON{
+		"list":    data,
+		"success": true,
+	})
+}
+
+var createVolumeHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.CreateVolumeOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	dataset, err := service.DefaultZFSManager.CreateVolume(body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	template := DatasetTemplate{}
+	template.Assign(&dataset)
+	dataset.Close()
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    template,
+	})
+}
+
+type ResizeVolumeRequestBody struct {
+	Path  string `json:"path"`
+	Size  string `json:"size"`
+	Force bool   `json:"force"`
+}
+
+var resizeVolumeHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body ResizeVolumeRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	resized, err := service.DefaultZFSManager.ResizeVolume(body.Path, body.Size, body.Force)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.VolumeInUseError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success":           true,
+		"filesystemResized": resized,
+	})
+}
+
+var deleteVolumeHandler haruka.RequestHandler = func(context *haruka.Context) {
+	datasetPath := context.GetQueryString("path")
+	err := service.DefaultZFSManager.DeleteVolume(datasetPath)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.VolumeInUseError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+type DatasetKeyRequestBody struct {
+	Dataset string `json:"dataset"`
+	Key     string `json:"key"`
+	KeyFile string `json:"keyFile"`
+}
+
+var loadDatasetKeyHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body DatasetKeyRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.LoadDatasetKey(body.Dataset, body.Key, body.KeyFile)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.InvalidKeyError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var unloadDatasetKeyHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body DatasetKeyRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.UnloadDatasetKey(body.Dataset)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+type ChangeDatasetKeyRequestBody struct {
+	Dataset    string                          `json:"dataset"`
+	Encryption service.DatasetEncryptionOption `json:"encryption"`
+}
+
+var changeDatasetKeyHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body ChangeDatasetKeyRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.ChangeDatasetKey(body.Dataset, body.Encryption)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.InvalidKeyError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var setDatasetAutoUnlockHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body DatasetKeyRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.SetDatasetAutoUnlock(body.Dataset, body.Key, body.KeyFile)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var removeDatasetAutoUnlockHandler haruka.RequestHandler = func(context *haruka.Context) {
+	dataset := context.GetQueryString("dataset")
+	err := service.RemoveDatasetAutoUnlock(dataset)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var cloneSnapshotHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body service.CloneSnapshotOption
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	dataset, err := service.DefaultZFSManager.CloneSnapshot(body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, cloneErrorStatus(err))
+		return
+	}
+	defer dataset.Close()
+	template := DatasetTemplate{}
+	template.Assign(&dataset)
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    template,
+	})
+}
+
+type PromoteDatasetRequestBody struct {
+	Dataset string `json:"dataset"`
+}
+
+var promoteDatasetHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body PromoteDatasetRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.PromoteDataset(body.Dataset)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var destroyCloneHandler haruka.RequestHandler = func(context *haruka.Context) {
+	dataset := context.GetQueryString("dataset")
+	err := service.DefaultZFSManager.DestroyClone(dataset)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+type SnapshotHoldRequestBody struct {
+	Dataset  string `json:"dataset"`
+	Snapshot string `json:"snapshot"`
+	Tag      string `json:"tag"`
+}
+
+var holdSnapshotHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body SnapshotHoldRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.HoldSnapshot(body.Dataset, body.Snapshot, body.Tag)
+	if err != nil {
+		AbortErrorWithStatus(err, context, snapshotFileErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var releaseSnapshotHandler haruka.RequestHandler = func(context *haruka.Context) {
+	dataset := context.GetQueryString("dataset")
+	snapshot := context.GetQueryString("snapshot")
+	tag := context.GetQueryString("tag")
+	err := service.DefaultZFSManager.ReleaseSnapshot(dataset, snapshot, tag)
+	if err != nil {
+		AbortErrorWithStatus(err, context, snapshotFileErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var bookmarkListHandler haruka.RequestHandler = func(context *haruka.Context) {
+	dataset := context.GetQueryString("dataset")
+	bookmarks, err := service.DefaultZFSManager.GetBookmarks(dataset)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"list":    bookmarks,
+	})
+}
+
+type CreateBookmarkRequestBody struct {
+	Dataset  string `json:"dataset"`
+	Snapshot string `json:"snapshot"`
+	Name     string `json:"name"`
+}
+
+var createBookmarkHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body CreateBookmarkRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.CreateBookmark(body.Dataset, body.Snapshot, body.Name)
+	if err != nil {
+		AbortErrorWithStatus(err, context, snapshotFileErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var deleteBookmarkHandler haruka.RequestHandler = func(context *haruka.Context) {
+	dataset := context.GetQueryString("dataset")
+	name := context.GetQueryString("name")
+	err := service.DefaultZFSManager.DeleteBookmark(dataset, name)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+type QuotaQuery struct {
+	Dataset string `hsource:"query" hname:"dataset"`
+	Type    string `hsource:"query" hname:"type"`
+}
+
+var quotaListHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var query QuotaQuery
+	err := context.BindingInput(&query)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	if len(query.Type) == 0 {
+		query.Type = "user"
+	}
+	usages, err := service.DefaultZFSManager.GetQuotaUsages(query.Dataset, query.Type)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.UnsupportedQuotaTypeError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"list":    usages,
+	})
+}
+
+type SetQuotaRequestBody struct {
+	Dataset string `json:"dataset"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Size    string `json:"size"`
+}
+
+var setQuotaHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body SetQuotaRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.SetQuota(body.Dataset, body.Type, body.Name, body.Size)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.UnsupportedQuotaTypeError) || errors.Is(err, service.QuotaTargetNotFoundError) {
+			status = http.StatusBadRequest
+		}
+		AbortErrorWithStatus(err, context, status)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var zfsEventListHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var filter service.ZFSEventQueryFilter
+	err := context.BindingInput(&filter)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	events, count, err := service.GetZFSEvents(filter)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	data := make([]ZFSEventTemplate, 0, len(events))
+	for _, event := range events {
+		template := ZFSEventTemplate{}
+		template.Assign(&event)
+		data = append(data, template)
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"list":    data,
+		"total":   count,
+	})
+}
+
+type AcknowledgeEventsRequestBody struct {
+	Ids []uint `json:"ids"`
+}
+
+var acknowledgeZFSEventsHandler haruka.RequestHandler = func(context *haruka.Context) {
+	var body AcknowledgeEventsRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.AcknowledgeZFSEvents(body.Ids)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var getSparePolicyHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	policy, err := service.GetSparePolicy(name)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	template := SparePolicyTemplate{}
+	template.Assign(policy)
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    template,
+	})
+}
+
+type SparePolicyRequestBody struct {
+	AutoSpare   bool `json:"autoSpare"`
+	AutoReplace bool `json:"autoReplace"`
+}
+
+var setSparePolicyHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	var body SparePolicyRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	policy, err := service.SetSparePolicy(name, body.AutoSpare, body.AutoReplace)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	template := SparePolicyTemplate{}
+	template.Assign(policy)
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    template,
+	})
+}
+
+var datasetSpaceHandler haruka.RequestHandler = func(context *haruka.Context) {
+	dataset := context.GetQueryString("dataset")
+	space, err := service.DefaultZFSManager.GetDatasetSpace(dataset)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	snapshots, err := service.DefaultZFSManager.GetSnapshotsSpace(dataset)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusInternalServerError)
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success":   true,
+		"data":      space,
+		"snapshots": snapshots,
+	})
+}
+
+type PoolTrimRequestBody struct {
+	Devices []string `json:"devices"`
+}
+
+var getPoolTrimStatusHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	status, err := service.DefaultZFSManager.GetTrimStatus(name)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+		"data":    status,
+	})
+}
+
+var startPoolTrimHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	var body PoolTrimRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.StartTrim(name, body.Devices)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+var cancelPoolTrimHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	devices := context.Request.URL.Query()["device"]
+	err := service.DefaultZFSManager.CancelTrim(name, devices)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
+
+type AutotrimRequestBody struct {
+	Enable bool `json:"enable"`
+}
+
+var setPoolAutotrimHandler haruka.RequestHandler = func(context *haruka.Context) {
+	name := context.GetPathParameterAsString("name")
+	var body AutotrimRequestBody
+	err := context.ParseJson(&body)
+	if err != nil {
+		AbortErrorWithStatus(err, context, http.StatusBadRequest)
+		return
+	}
+	err = service.DefaultZFSManager.SetAutotrim(name, body.Enable)
+	if err != nil {
+		AbortErrorWithStatus(err, context, zfsDeviceErrorStatus(err))
+		return
+	}
+	context.JSON(haruka.JSON{
+		"success": true,
+	})
+}
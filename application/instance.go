@@ -31,7 +31,25 @@ func RunApplication() {
 	e.Router.GET("/disks/info", getDiskInfo)
 	e.Router.POST("/disks/addpartition", addPartitionHandler)
 	e.Router.POST("/disks/removepartition", removePartitionHandler)
+	e.Router.POST("/disks/table", createPartitionTableHandler)
+	e.Router.POST("/disks/partition", createPartitionHandler)
+	e.Router.POST("/disks/partition/format", formatPartitionHandler)
+	e.Router.POST("/disks/partition/resize", resizePartitionHandler)
+	e.Router.POST("/disks/fstab/migrate", migrateFstabHandler)
+	e.Router.GET("/disks/power", getDiskPowerHandler)
+	e.Router.POST("/disks/power", setDiskPowerHandler)
+	e.Router.POST("/disks/wipe", secureWipeDiskHandler)
+	e.Router.GET("/raid", getRaidListHandler)
+	e.Router.POST("/raid", createRaidHandler)
+	e.Router.GET("/raid/{name}", getRaidHandler)
+	e.Router.DELETE("/raid/{name}", removeRaidHandler)
+	e.Router.POST("/raid/{name}/members", addRaidMemberHandler)
+	e.Router.DELETE("/raid/{name}/members/{device}", removeRaidMemberHandler)
+	e.Router.POST("/raid/{name}/format", formatRaidHandler)
 	e.Router.GET("/disk/smart", diskSmartHandler)
+	e.Router.GET("/disk/smart/history", diskSmartHistoryHandler)
+	e.Router.GET("/disk/smart/test", diskSelfTestListHandler)
+	e.Router.POST("/disk/smart/test", startDiskSelfTestHandler)
 	e.Router.POST("/share", createShareHandler)
 	e.Router.GET("/share", getShareFolderList)
 	e.Router.DELETE("/share", removeShareHandler)
@@ -51,19 +69,73 @@ func RunApplication() {
 	e.Router.DELETE("/storage", removeStorage)
 	e.Router.PATCH("/storage/{id}", updateStorageHandler)
 	e.Router.GET("/storage/{id}", getStorageDetailHandler)
+	e.Router.POST("/storage/{id}/unlock", unlockStorageHandler)
+	e.Router.POST("/storage/{id}/lock", lockStorageHandler)
+	e.Router.GET("/storage/{id}/keys", getStorageKeySlotsHandler)
+	e.Router.POST("/storage/{id}/keys", addStorageKeySlotHandler)
+	e.Router.DELETE("/storage/{id}/keys/{slot}", removeStorageKeySlotHandler)
+	e.Router.POST("/storage/{id}/autounlock", setStorageAutoUnlockHandler)
+	e.Router.DELETE("/storage/{id}/autounlock", removeStorageAutoUnlockHandler)
 	e.Router.POST("/zpool", createZFSPoolHandler)
 	e.Router.GET("/zpool/{name}/info", getZFSPoolHandler)
 	e.Router.POST("/zpool/conf", createZFSPoolWithNodeHandler)
+	e.Router.POST("/zpool/conf/preview", previewZFSPoolLayoutHandler)
+	e.Router.POST("/zpool/{name}/replace", replacePoolDeviceHandler)
+	e.Router.POST("/zpool/{name}/attach", attachPoolDeviceHandler)
+	e.Router.POST("/zpool/{name}/detach", detachPoolDeviceHandler)
+	e.Router.POST("/zpool/{name}/online", onlinePoolDeviceHandler)
+	e.Router.POST("/zpool/{name}/offline", offlinePoolDeviceHandler)
+	e.Router.POST("/zpool/{name}/add", addPoolVdevHandler)
+	e.Router.GET("/zpool/{name}/scan", getPoolScanStatusHandler)
+	e.Router.GET("/zpool/{name}/trim", getPoolTrimStatusHandler)
+	e.Router.POST("/zpool/{name}/trim", startPoolTrimHandler)
+	e.Router.DELETE("/zpool/{name}/trim", cancelPoolTrimHandler)
+	e.Router.POST("/zpool/{name}/autotrim", setPoolAutotrimHandler)
+	e.Router.GET("/zpool/{name}/sparepolicy", getSparePolicyHandler)
+	e.Router.POST("/zpool/{name}/sparepolicy", setSparePolicyHandler)
+	e.Router.POST("/zpool/{name}/export", exportPoolHandler)
+	e.Router.GET("/zpool/import", getImportablePoolListHandler)
+	e.Router.POST("/zpool/import", importPoolHandler)
 	e.Router.GET("/zpool", getZFSPoolListHandler)
 	e.Router.DELETE("/zpool", removePoolHandler)
 	e.Router.GET("/zpool/dataset", datasetListHandler)
 	e.Router.GET("/zfs/monitor", getZFSPoolsMonitorHandler)
+	e.Router.GET("/zfs/iostat", getZFSPoolsIOStatHandler)
+	e.Router.GET("/zfs/arc", getZFSARCHandler)
 	e.Router.POST("/zpool/dataset", createDatasetHandler)
 	e.Router.DELETE("/zpool/dataset", deleteDatasetHandler)
+	e.Router.GET("/zpool/dataset/props", getDatasetPropertiesHandler)
+	e.Router.POST("/zpool/dataset/props", updateDatasetPropertiesHandler)
+	e.Router.DELETE("/zpool/dataset/props", inheritDatasetPropertyHandler)
+	e.Router.POST("/zpool/dataset/key/load", loadDatasetKeyHandler)
+	e.Router.POST("/zpool/dataset/key/unload", unloadDatasetKeyHandler)
+	e.Router.POST("/zpool/dataset/key/change", changeDatasetKeyHandler)
+	e.Router.POST("/zpool/dataset/key/autounlock", setDatasetAutoUnlockHandler)
+	e.Router.DELETE("/zpool/dataset/key/autounlock", removeDatasetAutoUnlockHandler)
+	e.Router.GET("/zpool/events", zfsEventListHandler)
+	e.Router.POST("/zpool/events/ack", acknowledgeZFSEventsHandler)
+	e.Router.GET("/zpool/volume", volumeListHandler)
+	e.Router.POST("/zpool/volume", createVolumeHandler)
+	e.Router.DELETE("/zpool/volume", deleteVolumeHandler)
+	e.Router.POST("/zpool/volume/resize", resizeVolumeHandler)
 	e.Router.POST("/zpool/dataset/snapshot", createSnapshotHandler)
 	e.Router.GET("/zpool/dataset/snapshot", datasetSnapshotListHandler)
 	e.Router.DELETE("/zpool/dataset/snapshot", deleteSnapshotHandler)
 	e.Router.POST("/zpool/dataset/rollback", datasetSnapshotRollbackHandler)
+	e.Router.GET("/zpool/dataset/snapshot/diff", snapshotDiffHandler)
+	e.Router.GET("/zpool/dataset/snapshot/files", snapshotFilesHandler)
+	e.Router.POST("/zpool/dataset/snapshot/restore", restoreSnapshotFilesHandler)
+	e.Router.POST("/zpool/dataset/snapshot/hold", holdSnapshotHandler)
+	e.Router.DELETE("/zpool/dataset/snapshot/hold", releaseSnapshotHandler)
+	e.Router.GET("/zpool/dataset/bookmark", bookmarkListHandler)
+	e.Router.POST("/zpool/dataset/bookmark", createBookmarkHandler)
+	e.Router.DELETE("/zpool/dataset/bookmark", deleteBookmarkHandler)
+	e.Router.POST("/zpool/dataset/clone", cloneSnapshotHandler)
+	e.Router.GET("/zpool/dataset/quota", quotaListHandler)
+	e.Router.GET("/zpool/dataset/space", datasetSpaceHandler)
+	e.Router.POST("/zpool/dataset/quota", setQuotaHandler)
+	e.Router.DELETE("/zpool/dataset/clone", destroyCloneHandler)
+	e.Router.POST("/zpool/dataset/promote", promoteDatasetHandler)
 	e.Router.POST("/user/auth", generateAuthHandler)
 	e.Router.POST("/admin/auth", userLoginHandler)
 	e.Router.GET("/user/auth", checkTokenHandler)
@@ -88,6 +160,12 @@ func RunApplication() {
 	e.Router.GET("/system/users", listSystemUsersHandler)
 	e.Router.POST("/system/users/enable", enableSystemUserHandler)
 	e.Router.GET("/tasks", tasksListHandler)
+	e.Router.DELETE("/tasks/{id}", cancelTaskHandler)
+	e.Router.GET("/schedule", getScheduledJobListHandler)
+	e.Router.POST("/schedule", createScheduledJobHandler)
+	e.Router.PATCH("/schedule/{id}", updateScheduledJobHandler)
+	e.Router.DELETE("/schedule/{id}", deleteScheduledJobHandler)
+	e.Router.POST("/schedule/{id}/run", runScheduledJobHandler)
 	e.Router.GET("/path/readdir", ReadDirHandler)
 	e.Router.GET("/path/realpath", GetRealPathHandler)
 	e.Router.GET("/info", serviceInfoHandler)
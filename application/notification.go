@@ -3,9 +3,12 @@ package application
 import (
 	"github.com/allentom/haruka"
 	"github.com/gorilla/websocket"
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/projectxpolaris/youplus/service"
 	"github.com/rs/xid"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"strings"
 	"sync"
 )
 
@@ -14,6 +17,22 @@ const (
 	InstallDoneEvent    = "InstallDone"
 	UninstallErrorEvent = "UninstallError"
 	UninstallDoneEvent  = "UninstallDone"
+	RestoreErrorEvent   = "RestoreError"
+	RestoreDoneEvent    = "RestoreDone"
+	ZFSEventEvent       = "ZFSEvent"
+	PoolHealthEvent     = "PoolHealthChanged"
+	HotSpareStartEvent  = "HotSpareStart"
+	HotSpareDoneEvent   = "HotSpareDone"
+	HotSpareErrorEvent  = "HotSpareError"
+	PartitionDoneEvent  = "PartitionDone"
+	PartitionErrorEvent = "PartitionError"
+	WipeDoneEvent       = "WipeDone"
+	WipeErrorEvent      = "WipeError"
+	DiskAddedEvent      = "DiskAdded"
+	DiskRemovedEvent    = "DiskRemoved"
+	DiskChangedEvent    = "DiskChanged"
+	StorageStatusEvent  = "StorageStatusChanged"
+	RaidEvent           = "RaidEvent"
 )
 
 var WebsocketLogger = logrus.New().WithField("scope", "websocket")
@@ -61,6 +80,68 @@ func (m *NotificationManager) sendJSONToAll(data interface{}) {
 	}
 }
 
+func sendTaskNotification(event string, task service.Task) {
+	template := TaskTemplate{}
+	template.Assign(task)
+	DefaultNotificationManager.sendJSONToAll(haruka.JSON{
+		"event": event,
+		"data":  template,
+	})
+}
+
+// ListenServiceEvents forward events from background services to notification connections,
+// it must be called before services run so early events are not lost
+func ListenServiceEvents() {
+	service.DefaultSparePolicyEngine.SetCallback(service.HotSpareCallback{
+		OnStart: func(task *service.HotSpareTask) {
+			sendTaskNotification(HotSpareStartEvent, task)
+		},
+		OnDone: func(task *service.HotSpareTask) {
+			sendTaskNotification(HotSpareDoneEvent, task)
+		},
+		OnError: func(task *service.HotSpareTask) {
+			sendTaskNotification(HotSpareErrorEvent, task)
+		},
+	})
+	service.DefaultZFSEventWatcher.AddListener(func(event *database.ZFSEvent) {
+		template := ZFSEventTemplate{}
+		template.Assign(event)
+		eventName := ZFSEventEvent
+		if event.Class == service.PoolHealthChangeClass {
+			eventName = PoolHealthEvent
+		}
+		if strings.HasPrefix(event.Class, service.MDEventClassPrefix) {
+			eventName = RaidEvent
+		}
+		DefaultNotificationManager.sendJSONToAll(haruka.JSON{
+			"event": eventName,
+			"data":  template,
+		})
+	})
+	diskEventNames := map[string]string{
+		service.DiskEventAdd:    DiskAddedEvent,
+		service.DiskEventRemove: DiskRemovedEvent,
+		service.DiskEventChange: DiskChangedEvent,
+	}
+	service.DefaultDiskInventory.AddListener(func(events []service.DiskEvent, storages map[string]string) {
+		for _, event := range events {
+			DefaultNotificationManager.sendJSONToAll(haruka.JSON{
+				"event": diskEventNames[event.Action],
+				"data":  event.Disk,
+			})
+		}
+		for id, status := range storages {
+			DefaultNotificationManager.sendJSONToAll(haruka.JSON{
+				"event": StorageStatusEvent,
+				"data": haruka.JSON{
+					"id":     id,
+					"status": status,
+				},
+			})
+		}
+	})
+}
+
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		return true
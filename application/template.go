@@ -1,6 +1,8 @@
 package application
 
 import (
+	"strings"
+
 	libzfs "github.com/bicomsystems/go-libzfs"
 	"github.com/projectxpolaris/youplus/database"
 	"github.com/projectxpolaris/youplus/service"
@@ -9,9 +11,11 @@ import (
 const TimeLayout = "2006-01-02 15:04:05"
 
 type ZFSPoolTemplate struct {
-	Name   string             `json:"name,omitempty"`
-	Tree   ZFSTreeTemplate    `json:"tree,omitempty"`
-	Shares []ShareFolderBrief `json:"shares,omitempty"`
+	Name   string                     `json:"name,omitempty"`
+	Tree   ZFSTreeTemplate            `json:"tree,omitempty"`
+	Scan   *service.ZFSPoolScanStatus `json:"scan,omitempty"`
+	Trim   *service.PoolTrimStatus    `json:"trim,omitempty"`
+	Shares []ShareFolderBrief         `json:"shares,omitempty"`
 }
 
 func (t *ZFSPoolTemplate) Assign(pool libzfs.Pool) error {
@@ -28,6 +32,8 @@ func (t *ZFSPoolTemplate) Assign(pool libzfs.Pool) error {
 		t.Tree = ZFSTreeTemplate{}
 		t.Tree.Assign(&vt)
 	}
+	scan := service.NewZFSPoolScanStatus(vt.ScanStat)
+	t.Scan = &scan
 	// attach related share folders by pool name
 	var storage database.ZFSStorage
 	if err := database.Instance.Where("mount_point = ?", name).First(&storage).Error; err == nil && storage.ID != "" {
@@ -42,6 +48,25 @@ func (t *ZFSPoolTemplate) Assign(pool libzfs.Pool) error {
 	return nil
 }
 
+type ImportablePoolTemplate struct {
+	Name    string          `json:"name"`
+	GUID    string          `json:"guid"`
+	Comment string          `json:"comment"`
+	State   string          `json:"state"`
+	Status  string          `json:"status"`
+	Tree    ZFSTreeTemplate `json:"tree"`
+}
+
+func (t *ImportablePoolTemplate) Assign(pool *service.ImportablePool) {
+	t.Name = pool.Name
+	t.GUID = pool.GUID
+	t.Comment = pool.Comment
+	t.State = pool.State
+	t.Status = pool.Status
+	t.Tree = ZFSTreeTemplate{}
+	t.Tree.Assign(&pool.VDevs)
+}
+
 type ShareFolderBrief struct {
 	Name string `json:"name"`
 	Path string `json:"path"`
@@ -55,9 +80,14 @@ type ZFSTreeTemplate struct {
 	Free      uint64            `json:"free,omitempty"`
 	Alloc     uint64            `json:"alloc,omitempty"`
 	Path      string            `json:"path"`
+	State     string            `json:"state"`
+	ReadErr   uint64            `json:"readErr"`
+	WriteErr  uint64            `json:"writeErr"`
+	CksumErr  uint64            `json:"cksumErr"`
 	Devices   []ZFSTreeTemplate `json:"devices"`
 	L2Cache   []ZFSTreeTemplate `json:"l2Cache"`
 	Spares    []ZFSTreeTemplate `json:"spares"`
+	Logs      []ZFSTreeTemplate `json:"logs"`
 }
 
 func (t *ZFSTreeTemplate) Assign(tree *libzfs.VDevTree) {
@@ -67,6 +97,10 @@ func (t *ZFSTreeTemplate) Assign(tree *libzfs.VDevTree) {
 	t.Alloc = tree.Stat.Alloc
 	t.Free = tree.Stat.Space - tree.Stat.Alloc
 	t.Path = tree.Path
+	t.State = tree.Stat.State.String()
+	t.ReadErr = tree.Stat.ReadErrors
+	t.WriteErr = tree.Stat.WriteErrors
+	t.CksumErr = tree.Stat.ChecksumErrors
 	t.Devices = []ZFSTreeTemplate{}
 	if tree.Devices != nil {
 		for _, device := range tree.Devices {
@@ -91,20 +125,29 @@ func (t *ZFSTreeTemplate) Assign(tree *libzfs.VDevTree) {
 			t.Spares = append(t.Spares, template)
 		}
 	}
+	t.Logs = []ZFSTreeTemplate{}
+	if tree.Logs != nil {
+		template := ZFSTreeTemplate{}
+		template.Assign(tree.Logs)
+		t.Logs = append(t.Logs, template)
+	}
 }
 
 type StorageTemplate struct {
 	Id       string                   `json:"id"`
 	Name     string                   `json:"name"`
 	Type     string                   `json:"type"`
+	Status   string                   `json:"status"`
 	Used     int64                    `json:"used"`
 	Total    int64                    `json:"total"`
 	ZFS      *StorageZFSTemplate      `json:"zfs,omitempty"`
 	DiskPart *StorageDiskPartTemplate `json:"diskPart,omitempty"`
 	Path     *StoragePathTemplate     `json:"path,omitempty"`
+	LUKS     *StorageLUKSTemplate     `json:"luks,omitempty"`
 }
 type StorageZFSTemplate struct {
-	Name string `json:"name"`
+	Name  string                `json:"name"`
+	Space *service.DatasetSpace `json:"space,omitempty"`
 }
 type StorageDiskPartTemplate struct {
 	Name string `json:"name"`
@@ -112,6 +155,12 @@ type StorageDiskPartTemplate struct {
 type StoragePathTemplate struct {
 	Path string `json:"path"`
 }
+type StorageLUKSTemplate struct {
+	Source     string `json:"source"`
+	FSType     string `json:"fsType"`
+	Locked     bool   `json:"locked"`
+	AutoUnlock bool   `json:"autoUnlock"`
+}
 
 type StorageDetailTemplate struct {
 	StorageTemplate
@@ -121,6 +170,7 @@ type StorageDetailTemplate struct {
 func (t *StorageTemplate) Assign(storage service.Storage) {
 	t.Id = storage.GetId()
 	t.Name = storage.GetName()
+	t.Status = service.DefaultStoragePool.GetStorageStatus(t.Id)
 	switch storage.(type) {
 	case *service.DiskPartStorage:
 		t.Type = "Parted"
@@ -132,12 +182,22 @@ func (t *StorageTemplate) Assign(storage service.Storage) {
 		zfsStorage := storage.(*service.ZFSPoolStorage)
 		t.ZFS = &StorageZFSTemplate{}
 		t.ZFS.Name = zfsStorage.PoolName
+		t.ZFS.Space, _ = service.DefaultZFSManager.GetDatasetSpace(zfsStorage.MountPoint)
 	case *service.PathStorage:
 		t.Type = "Path"
 		pathStorage := storage.(*service.PathStorage)
 		t.Path = &StoragePathTemplate{
 			Path: pathStorage.Path,
 		}
+	case *service.LUKSPartStorage:
+		t.Type = "LUKSPart"
+		luksStorage := storage.(*service.LUKSPartStorage)
+		t.LUKS = &StorageLUKSTemplate{
+			Source:     luksStorage.Source,
+			FSType:     luksStorage.FSType,
+			Locked:     luksStorage.IsLocked(),
+			AutoUnlock: luksStorage.AutoUnlock,
+		}
 	}
 	t.Used, t.Total, _ = storage.GetUsage()
 }
@@ -205,15 +265,43 @@ type Props struct {
 	Source string `json:"source"`
 }
 type DatasetTemplate struct {
-	Pool          string  `json:"pool"`
-	Path          string  `json:"path"`
-	SnapshotCount int     `json:"snapshotCount,omitempty"`
-	Props         []Props `json:"props,omitempty"`
+	Pool          string                 `json:"pool"`
+	Path          string                 `json:"path"`
+	Type          string                 `json:"type"`
+	Device        string                 `json:"device,omitempty"`
+	Encryption    string                 `json:"encryption,omitempty"`
+	KeyStatus     string                 `json:"keyStatus,omitempty"`
+	Origin        string                 `json:"origin,omitempty"`
+	Clones        []string               `json:"clones,omitempty"`
+	Holds         []service.SnapshotHold `json:"holds,omitempty"`
+	Space         service.DatasetSpace   `json:"space"`
+	SnapshotCount int                    `json:"snapshotCount,omitempty"`
+	Props         []Props                `json:"props,omitempty"`
 }
 
 func (t *DatasetTemplate) Assign(dataset *libzfs.Dataset) {
 	t.Pool = dataset.PoolName()
 	t.Path, _ = dataset.Path()
+	for name, datasetType := range service.DatasetTypeMapping {
+		if dataset.Type == datasetType {
+			t.Type = name
+		}
+	}
+	if dataset.Type == libzfs.DatasetTypeVolume {
+		t.Device = service.GetVolumeDevicePath(t.Path)
+	}
+	if encryption, ok := dataset.Properties[libzfs.DatasetPropEncryption]; ok && encryption.Value != "off" {
+		t.Encryption = encryption.Value
+		t.KeyStatus = dataset.Properties[libzfs.DatasetPropKeyStatus].Value
+	}
+	t.Space = service.NewDatasetSpace(dataset)
+	if origin := dataset.Properties[libzfs.DatasetPropOrigin].Value; len(origin) > 0 && origin != "-" {
+		t.Origin = origin
+	}
+	if dataset.IsSnapshot() {
+		t.Clones = service.GetSnapshotClones(dataset)
+		t.Holds = service.GetSnapshotHolds(dataset)
+	}
 	t.Props = make([]Props, 0)
 	for prop, property := range dataset.Properties {
 		t.Props = append(t.Props, Props{
@@ -251,3 +339,128 @@ type SMBSharesStatusTemplate struct {
 	Machine   string `json:"machine"`
 	ConnectAt string `json:"connectAt"`
 }
+
+type ZFSEventTemplate struct {
+	Id           uint   `json:"id"`
+	Class        string `json:"class"`
+	Pool         string `json:"pool"`
+	Vdev         string `json:"vdev,omitempty"`
+	State        string `json:"state,omitempty"`
+	Level        string `json:"level"`
+	Message      string `json:"message"`
+	Time         string `json:"time"`
+	Acknowledged bool   `json:"acknowledged"`
+}
+
+func (t *ZFSEventTemplate) Assign(event *database.ZFSEvent) {
+	t.Id = event.ID
+	t.Class = event.Class
+	t.Pool = event.Pool
+	t.Vdev = event.Vdev
+	t.State = event.State
+	t.Level = event.Level
+	t.Message = event.Message
+	t.Time = event.Time.Format(taskTimeFormat)
+	t.Acknowledged = event.Acknowledged
+}
+
+type SparePolicyTemplate struct {
+	Pool        string `json:"pool"`
+	AutoSpare   bool   `json:"autoSpare"`
+	AutoReplace bool   `json:"autoReplace"`
+}
+
+func (t *SparePolicyTemplate) Assign(policy *database.SparePolicy) {
+	t.Pool = policy.Pool
+	t.AutoSpare = policy.AutoSpare
+	t.AutoReplace = policy.AutoReplace
+}
+
+type ScheduledJobTemplate struct {
+	Id        uint   `json:"id"`
+	Type      string `json:"type"`
+	Target    string `json:"target"`
+	Interval  int64  `json:"interval"`
+	Enable    bool   `json:"enable"`
+	NextRun   int64  `json:"nextRun"`
+	LastRun   int64  `json:"lastRun,omitempty"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+func (t *ScheduledJobTemplate) Assign(job *database.ScheduledJob) {
+	t.Id = job.ID
+	t.Type = job.Type
+	t.Target = job.Target
+	t.Interval = job.Interval
+	t.Enable = job.Enable
+	t.NextRun = job.NextRun.Unix()
+	if job.LastRun != nil {
+		t.LastRun = job.LastRun.Unix()
+	}
+	t.LastError = job.LastError
+}
+
+type SmartSampleTemplate struct {
+	Time          int64    `json:"time"`
+	Healthy       bool     `json:"healthy"`
+	Reallocated   int64    `json:"reallocated"`
+	Pending       int64    `json:"pending"`
+	Uncorrectable int64    `json:"uncorrectable"`
+	CRCErrors     int64    `json:"crcErrors"`
+	Temperature   int64    `json:"temperature"`
+	PowerOnHours  int64    `json:"powerOnHours"`
+	MediaErrors   int64    `json:"mediaErrors"`
+	PercentUsed   int64    `json:"percentUsed"`
+	Failing       []string `json:"failing"`
+}
+
+func (t *SmartSampleTemplate) Assign(sample *database.SmartSample) {
+	t.Time = sample.Time.Unix()
+	t.Healthy = sample.Healthy
+	t.Reallocated = sample.Reallocated
+	t.Pending = sample.Pending
+	t.Uncorrectable = sample.Uncorrectable
+	t.CRCErrors = sample.CRCErrors
+	t.Temperature = sample.Temperature
+	t.PowerOnHours = sample.PowerOnHours
+	t.MediaErrors = sample.MediaErrors
+	t.PercentUsed = sample.PercentUsed
+	t.Failing = []string{}
+	if len(sample.Failing) > 0 {
+		t.Failing = strings.Split(sample.Failing, ",")
+	}
+}
+
+type SmartTestResultTemplate struct {
+	Type          string `json:"type"`
+	Status        string `json:"status"`
+	Passed        bool   `json:"passed"`
+	LifetimeHours int64  `json:"lifetimeHours"`
+	Recorded      int64  `json:"recorded"`
+}
+
+func (t *SmartTestResultTemplate) Assign(result *database.SmartTestResult) {
+	t.Type = result.Type
+	t.Status = result.Status
+	t.Passed = result.Passed
+	t.LifetimeHours = result.LifetimeHours
+	t.Recorded = result.CreatedAt.Unix()
+}
+
+type DiskPowerTemplate struct {
+	Disk           string `json:"disk"`
+	State          string `json:"state"`
+	APMLevel       int    `json:"apmLevel"`
+	StandbyTimeout int    `json:"standbyTimeout"`
+	WriteCache     string `json:"writeCache"`
+}
+
+func (t *DiskPowerTemplate) Assign(status *service.DiskPowerStatus) {
+	t.Disk = status.Disk
+	t.State = status.State
+	if status.Setting != nil {
+		t.APMLevel = status.Setting.APMLevel
+		t.StandbyTimeout = status.Setting.StandbyTimeout
+		t.WriteCache = status.Setting.WriteCache
+	}
+}
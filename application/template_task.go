@@ -25,6 +25,18 @@ func (t *TaskTemplate) Assign(task service.Task) {
 	case *service.UnInstallAppTask:
 		t.Type = "UninstallApp"
 		t.Extra = task.(*service.UnInstallAppTask).Extra
+	case *service.RestoreSnapshotFilesTask:
+		t.Type = "RestoreSnapshotFiles"
+		t.Extra = task.(*service.RestoreSnapshotFilesTask).Extra
+	case *service.HotSpareTask:
+		t.Type = "HotSpare"
+		t.Extra = task.(*service.HotSpareTask).Extra
+	case *service.PartitionTask:
+		t.Type = "Partition"
+		t.Extra = task.(*service.PartitionTask).Extra
+	case *service.WipeTask:
+		t.Type = "WipeDisk"
+		t.Extra = task.(*service.WipeTask).Extra
 	}
 	t.Updated = task.GetUpdated().Format(taskTimeFormat)
 	t.Created = task.GetCreated().Format(taskTimeFormat)
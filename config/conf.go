@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/sirupsen/logrus"
 )
@@ -34,11 +35,25 @@ type AppConfig struct {
 	DatabaseConfig *DatabaseConfig `json:"database"`
 }
 
-func LoadAppConfig() error {
+func configFileName() string {
 	configFileName := os.Getenv("CONFIG_NAME")
 	if len(configFileName) == 0 {
 		configFileName = "./config.json"
 	}
+	return configFileName
+}
+
+// ConfigDir return absolute path of directory which contains config file
+func ConfigDir() string {
+	dir, err := filepath.Abs(filepath.Dir(configFileName()))
+	if err != nil {
+		return filepath.Dir(configFileName())
+	}
+	return dir
+}
+
+func LoadAppConfig() error {
+	configFileName := configFileName()
 	logrus.Info("config from", configFileName)
 	jsonFile, err := os.Open(configFileName)
 	if err != nil {
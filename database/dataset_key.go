@@ -0,0 +1,10 @@
+package database
+
+import "gorm.io/gorm"
+
+// DatasetKey is key file used to unlock encrypted dataset at boot
+type DatasetKey struct {
+	gorm.Model
+	Dataset string
+	KeyFile string
+}
@@ -0,0 +1,15 @@
+package database
+
+import "gorm.io/gorm"
+
+// DiskPowerSetting is power setting of disk applied at boot, Disk is stable device path
+type DiskPowerSetting struct {
+	gorm.Model
+	Disk string
+	// APMLevel is 1-255, zero keeps drive default
+	APMLevel int
+	// StandbyTimeout is idle seconds before spin down, zero disables spin down
+	StandbyTimeout int
+	// WriteCache is on, off or empty to keep drive default
+	WriteCache string
+}
@@ -40,6 +40,14 @@ func ConnectToDatabase() (err error) {
 		&App{},
 		&ConfigItem{},
 		&FolderStorage{},
+		&DatasetKey{},
+		&ZFSEvent{},
+		&SparePolicy{},
+		&ScheduledJob{},
+		&SmartSample{},
+		&SmartTestResult{},
+		&DiskPowerSetting{},
+		&LUKSStorage{},
 	)
 	if err != nil {
 		return
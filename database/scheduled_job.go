@@ -0,0 +1,19 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ScheduledJob run job of Type on Target every Interval seconds
+type ScheduledJob struct {
+	gorm.Model
+	Type      string
+	Target    string
+	Interval  int64
+	Enable    bool
+	NextRun   time.Time
+	LastRun   *time.Time
+	LastError string
+}
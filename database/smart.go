@@ -0,0 +1,36 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SmartSample is key smart attributes of disk at sample time, fields not reported by disk are zero,
+// Disk is stable device path
+type SmartSample struct {
+	gorm.Model
+	Disk          string
+	Time          time.Time
+	Healthy       bool
+	Reallocated   int64
+	Pending       int64
+	Uncorrectable int64
+	CRCErrors     int64
+	Temperature   int64
+	PowerOnHours  int64
+	MediaErrors   int64
+	PercentUsed   int64
+	// Failing is comma separated names of attributes at or below threshold
+	Failing string
+}
+
+// SmartTestResult is entry of disk self-test log, Disk is stable device path
+type SmartTestResult struct {
+	gorm.Model
+	Disk          string
+	Type          string
+	Status        string
+	Passed        bool
+	LifetimeHours int64
+}
@@ -0,0 +1,11 @@
+package database
+
+import "gorm.io/gorm"
+
+// SparePolicy is how failed devices of pool are handled
+type SparePolicy struct {
+	gorm.Model
+	Pool        string
+	AutoSpare   bool
+	AutoReplace bool
+}
@@ -20,3 +20,16 @@ type FolderStorage struct {
 	Name string `json:"name"`
 	Path string `json:"path"`
 }
+
+// LUKSStorage is partition encrypted with luks, mapped device is mounted on MountPoint when unlocked
+type LUKSStorage struct {
+	ID         string
+	MountPoint string
+	Name       string
+	Source     string
+	MapperName string
+	FSType     string
+	// KeyFile is used to unlock at boot when AutoUnlock is set
+	KeyFile    string
+	AutoUnlock bool
+}
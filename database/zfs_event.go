@@ -0,0 +1,20 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ZFSEvent is zfs event or pool health change recorded by event watcher
+type ZFSEvent struct {
+	gorm.Model
+	Class        string
+	Pool         string
+	Vdev         string
+	State        string
+	Level        string
+	Message      string
+	Time         time.Time
+	Acknowledged bool
+}
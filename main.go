@@ -64,6 +64,7 @@ func Program() {
 	InitEnv()
 	logger.Info("load init monitor")
 	service.DefaultMonitor.Run()
+	service.DefaultARCMonitor.Run()
 	logger.Info("load network manager")
 	err := service.DefaultNetworkManager.Load()
 	if err != nil {
@@ -80,14 +81,41 @@ func Program() {
 	if err != nil {
 		logger.Fatal(err)
 	}
+	migrations, err := service.MigrateFstabToStableSpec()
+	if err != nil {
+		logger.Error(err)
+	}
+	for _, migration := range migrations {
+		logger.Infof("fstab entry of %s now use %s instead of %s", migration.File, migration.To, migration.From)
+	}
+	logger.Info("unlock encrypted datasets")
+	err = service.UnlockDatasetsAtBoot()
+	if err != nil {
+		logger.Error(err)
+	}
 	logger.Info("load storage")
 	err = service.DefaultStoragePool.LoadStorage()
 	if err != nil {
 		logger.Fatal(err)
 	}
+	logger.Info("unlock luks storages")
+	service.UnlockLUKSStoragesAtBoot()
+	application.ListenServiceEvents()
+	logger.Info("start disk inventory")
+	service.DefaultDiskInventory.Run()
 	logger.Info("sync zfs mounts and smb shares")
 	_, _, _ = service.SyncZFSMountsToStorage()
 	_, _ = service.SyncSmbSharesToDB()
+	logger.Info("start zfs event watcher")
+	service.DefaultZFSEventWatcher.Run()
+	service.DefaultSparePolicyEngine.Run()
+	service.DefaultPoolIOStatMonitor.Run()
+	service.DefaultMDMonitor.Run()
+	logger.Info("apply disk power settings")
+	service.ApplyDiskPowerSettings()
+	logger.Info("start scheduler")
+	service.DefaultScheduler.Run()
+	service.DefaultSmartMonitor.Run()
 	logger.Info("init filesystem")
 	err = service.InitFileSystem()
 	if err != nil {
@@ -87,7 +87,7 @@ func (s Server) CreateDataset(ctx context.Context, in *CreateDatasetRequest) (*A
 	if err != nil {
 		return nil, err
 	}
-	_, err = service.DefaultZFSManager.CreateDataset(datasetPath)
+	_, err = service.DefaultZFSManager.CreateDataset(datasetPath, in.GetProps())
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +147,31 @@ func (s Server) RollbackDataset(ctx context.Context, in *RollbackDatasetRequest)
 	return &ActionReply{Success: &success}, nil
 }
 
+func (s Server) DiffSnapshot(ctx context.Context, in *DiffSnapshotRequest) (*DiffSnapshotReply, error) {
+	entries, total, err := service.DefaultZFSManager.GetSnapshotDiff(service.SnapshotDiffOption{
+		Dataset:  in.GetDataset(),
+		From:     in.GetFrom(),
+		To:       in.GetTo(),
+		Page:     int(in.GetPage()),
+		PageSize: int(in.GetPageSize()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	replyEntries := make([]*SnapshotDiffEntry, 0, len(entries))
+	for _, entry := range entries {
+		change, fileType, path := entry.Change, entry.Type, entry.Path
+		replyEntry := &SnapshotDiffEntry{Change: &change, Type: &fileType, Path: &path}
+		if len(entry.NewPath) > 0 {
+			newPath := entry.NewPath
+			replyEntry.NewPath = &newPath
+		}
+		replyEntries = append(replyEntries, replyEntry)
+	}
+	count := int64(total)
+	return &DiffSnapshotReply{Entries: replyEntries, Total: &count}, nil
+}
+
 func (s Server) RegisterEntry(ctx context.Context, in *RegisterEntryRequest) (*ActionReply, error) {
 	service.DefaultRegisterManager.RegisterApp(&service.Entry{
 		Name:     *in.Name,
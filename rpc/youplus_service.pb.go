@@ -119,7 +119,8 @@ type CreateDatasetRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Path *string `protobuf:"bytes,1,req,name=path" json:"path,omitempty"`
+	Path  *string           `protobuf:"bytes,1,req,name=path" json:"path,omitempty"`
+	Props map[string]string `protobuf:"bytes,2,rep,name=props" json:"props,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (x *CreateDatasetRequest) Reset() {
@@ -161,6 +162,13 @@ func (x *CreateDatasetRequest) GetPath() string {
 	return ""
 }
 
+func (x *CreateDatasetRequest) GetProps() map[string]string {
+	if x != nil {
+		return x.Props
+	}
+	return nil
+}
+
 type DeleteDatasetRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -373,6 +381,211 @@ func (x *RollbackDatasetRequest) GetSnapshot() string {
 	return ""
 }
 
+type DiffSnapshotRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dataset  *string `protobuf:"bytes,1,req,name=dataset" json:"dataset,omitempty"`
+	From     *string `protobuf:"bytes,2,req,name=from" json:"from,omitempty"`
+	To       *string `protobuf:"bytes,3,opt,name=to" json:"to,omitempty"`
+	Page     *int64  `protobuf:"varint,4,opt,name=page" json:"page,omitempty"`
+	PageSize *int64  `protobuf:"varint,5,opt,name=pageSize" json:"pageSize,omitempty"`
+}
+
+func (x *DiffSnapshotRequest) Reset() {
+	*x = DiffSnapshotRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_youplus_service_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiffSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffSnapshotRequest) ProtoMessage() {}
+
+func (x *DiffSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_youplus_service_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*DiffSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_youplus_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DiffSnapshotRequest) GetDataset() string {
+	if x != nil && x.Dataset != nil {
+		return *x.Dataset
+	}
+	return ""
+}
+
+func (x *DiffSnapshotRequest) GetFrom() string {
+	if x != nil && x.From != nil {
+		return *x.From
+	}
+	return ""
+}
+
+func (x *DiffSnapshotRequest) GetTo() string {
+	if x != nil && x.To != nil {
+		return *x.To
+	}
+	return ""
+}
+
+func (x *DiffSnapshotRequest) GetPage() int64 {
+	if x != nil && x.Page != nil {
+		return *x.Page
+	}
+	return 0
+}
+
+func (x *DiffSnapshotRequest) GetPageSize() int64 {
+	if x != nil && x.PageSize != nil {
+		return *x.PageSize
+	}
+	return 0
+}
+
+type SnapshotDiffEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Change  *string `protobuf:"bytes,1,req,name=change" json:"change,omitempty"`
+	Type    *string `protobuf:"bytes,2,req,name=type" json:"type,omitempty"`
+	Path    *string `protobuf:"bytes,3,req,name=path" json:"path,omitempty"`
+	NewPath *string `protobuf:"bytes,4,opt,name=newPath" json:"newPath,omitempty"`
+}
+
+func (x *SnapshotDiffEntry) Reset() {
+	*x = SnapshotDiffEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_youplus_service_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotDiffEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotDiffEntry) ProtoMessage() {}
+
+func (x *SnapshotDiffEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_youplus_service_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotDiffEntry.ProtoReflect.Descriptor instead.
+func (*SnapshotDiffEntry) Descriptor() ([]byte, []int) {
+	return file_youplus_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SnapshotDiffEntry) GetChange() string {
+	if x != nil && x.Change != nil {
+		return *x.Change
+	}
+	return ""
+}
+
+func (x *SnapshotDiffEntry) GetType() string {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ""
+}
+
+func (x *SnapshotDiffEntry) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *SnapshotDiffEntry) GetNewPath() string {
+	if x != nil && x.NewPath != nil {
+		return *x.NewPath
+	}
+	return ""
+}
+
+type DiffSnapshotReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*SnapshotDiffEntry `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+	Total   *int64               `protobuf:"varint,2,req,name=total" json:"total,omitempty"`
+}
+
+func (x *DiffSnapshotReply) Reset() {
+	*x = DiffSnapshotReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_youplus_service_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiffSnapshotReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffSnapshotReply) ProtoMessage() {}
+
+func (x *DiffSnapshotReply) ProtoReflect() protoreflect.Message {
+	mi := &file_youplus_service_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffSnapshotReply.ProtoReflect.Descriptor instead.
+func (*DiffSnapshotReply) Descriptor() ([]byte, []int) {
+	return file_youplus_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DiffSnapshotReply) GetEntries() []*SnapshotDiffEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *DiffSnapshotReply) GetTotal() int64 {
+	if x != nil && x.Total != nil {
+		return *x.Total
+	}
+	return 0
+}
+
 type ActionReply struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -386,7 +599,7 @@ type ActionReply struct {
 func (x *ActionReply) Reset() {
 	*x = ActionReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[7]
+		mi := &file_youplus_service_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -399,7 +612,7 @@ func (x *ActionReply) String() string {
 func (*ActionReply) ProtoMessage() {}
 
 func (x *ActionReply) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[7]
+	mi := &file_youplus_service_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -412,7 +625,7 @@ func (x *ActionReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ActionReply.ProtoReflect.Descriptor instead.
 func (*ActionReply) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{7}
+	return file_youplus_service_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ActionReply) GetSuccess() bool {
@@ -447,7 +660,7 @@ type GetDatasetInfoRequest struct {
 func (x *GetDatasetInfoRequest) Reset() {
 	*x = GetDatasetInfoRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[8]
+		mi := &file_youplus_service_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -460,7 +673,7 @@ func (x *GetDatasetInfoRequest) String() string {
 func (*GetDatasetInfoRequest) ProtoMessage() {}
 
 func (x *GetDatasetInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[8]
+	mi := &file_youplus_service_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -473,7 +686,7 @@ func (x *GetDatasetInfoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDatasetInfoRequest.ProtoReflect.Descriptor instead.
 func (*GetDatasetInfoRequest) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{8}
+	return file_youplus_service_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *GetDatasetInfoRequest) GetDataset() string {
@@ -494,7 +707,7 @@ type Snapshot struct {
 func (x *Snapshot) Reset() {
 	*x = Snapshot{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[9]
+		mi := &file_youplus_service_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -507,7 +720,7 @@ func (x *Snapshot) String() string {
 func (*Snapshot) ProtoMessage() {}
 
 func (x *Snapshot) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[9]
+	mi := &file_youplus_service_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -520,7 +733,7 @@ func (x *Snapshot) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
 func (*Snapshot) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{9}
+	return file_youplus_service_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *Snapshot) GetName() string {
@@ -542,7 +755,7 @@ type GetDatasetInfoReply struct {
 func (x *GetDatasetInfoReply) Reset() {
 	*x = GetDatasetInfoReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[10]
+		mi := &file_youplus_service_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -555,7 +768,7 @@ func (x *GetDatasetInfoReply) String() string {
 func (*GetDatasetInfoReply) ProtoMessage() {}
 
 func (x *GetDatasetInfoReply) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[10]
+	mi := &file_youplus_service_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -568,7 +781,7 @@ func (x *GetDatasetInfoReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDatasetInfoReply.ProtoReflect.Descriptor instead.
 func (*GetDatasetInfoReply) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{10}
+	return file_youplus_service_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *GetDatasetInfoReply) GetPath() string {
@@ -598,7 +811,7 @@ type RegisterEntryRequest struct {
 func (x *RegisterEntryRequest) Reset() {
 	*x = RegisterEntryRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[11]
+		mi := &file_youplus_service_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -611,7 +824,7 @@ func (x *RegisterEntryRequest) String() string {
 func (*RegisterEntryRequest) ProtoMessage() {}
 
 func (x *RegisterEntryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[11]
+	mi := &file_youplus_service_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -624,7 +837,7 @@ func (x *RegisterEntryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterEntryRequest.ProtoReflect.Descriptor instead.
 func (*RegisterEntryRequest) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{11}
+	return file_youplus_service_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *RegisterEntryRequest) GetName() string {
@@ -659,7 +872,7 @@ type UnregisterEntryRequest struct {
 func (x *UnregisterEntryRequest) Reset() {
 	*x = UnregisterEntryRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[12]
+		mi := &file_youplus_service_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -672,7 +885,7 @@ func (x *UnregisterEntryRequest) String() string {
 func (*UnregisterEntryRequest) ProtoMessage() {}
 
 func (x *UnregisterEntryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[12]
+	mi := &file_youplus_service_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -685,7 +898,7 @@ func (x *UnregisterEntryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnregisterEntryRequest.ProtoReflect.Descriptor instead.
 func (*UnregisterEntryRequest) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{12}
+	return file_youplus_service_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *UnregisterEntryRequest) GetInstance() string {
@@ -707,7 +920,7 @@ type UpdateEntryExportRequest struct {
 func (x *UpdateEntryExportRequest) Reset() {
 	*x = UpdateEntryExportRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[13]
+		mi := &file_youplus_service_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -720,7 +933,7 @@ func (x *UpdateEntryExportRequest) String() string {
 func (*UpdateEntryExportRequest) ProtoMessage() {}
 
 func (x *UpdateEntryExportRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[13]
+	mi := &file_youplus_service_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -733,7 +946,7 @@ func (x *UpdateEntryExportRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateEntryExportRequest.ProtoReflect.Descriptor instead.
 func (*UpdateEntryExportRequest) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{13}
+	return file_youplus_service_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *UpdateEntryExportRequest) GetData() string {
@@ -763,7 +976,7 @@ type HeartbeatRequest struct {
 func (x *HeartbeatRequest) Reset() {
 	*x = HeartbeatRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[14]
+		mi := &file_youplus_service_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -776,7 +989,7 @@ func (x *HeartbeatRequest) String() string {
 func (*HeartbeatRequest) ProtoMessage() {}
 
 func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[14]
+	mi := &file_youplus_service_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -789,7 +1002,7 @@ func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
 func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{14}
+	return file_youplus_service_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *HeartbeatRequest) GetName() string {
@@ -828,7 +1041,7 @@ type GenerateTokenReply struct {
 func (x *GenerateTokenReply) Reset() {
 	*x = GenerateTokenReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[15]
+		mi := &file_youplus_service_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -841,7 +1054,7 @@ func (x *GenerateTokenReply) String() string {
 func (*GenerateTokenReply) ProtoMessage() {}
 
 func (x *GenerateTokenReply) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[15]
+	mi := &file_youplus_service_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -854,7 +1067,7 @@ func (x *GenerateTokenReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GenerateTokenReply.ProtoReflect.Descriptor instead.
 func (*GenerateTokenReply) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{15}
+	return file_youplus_service_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *GenerateTokenReply) GetSuccess() bool {
@@ -904,7 +1117,7 @@ type GenerateTokenRequest struct {
 func (x *GenerateTokenRequest) Reset() {
 	*x = GenerateTokenRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[16]
+		mi := &file_youplus_service_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -917,7 +1130,7 @@ func (x *GenerateTokenRequest) String() string {
 func (*GenerateTokenRequest) ProtoMessage() {}
 
 func (x *GenerateTokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[16]
+	mi := &file_youplus_service_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -930,7 +1143,7 @@ func (x *GenerateTokenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GenerateTokenRequest.ProtoReflect.Descriptor instead.
 func (*GenerateTokenRequest) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{16}
+	return file_youplus_service_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *GenerateTokenRequest) GetUsername() string {
@@ -958,7 +1171,7 @@ type CheckTokenRequest struct {
 func (x *CheckTokenRequest) Reset() {
 	*x = CheckTokenRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[17]
+		mi := &file_youplus_service_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -971,7 +1184,7 @@ func (x *CheckTokenRequest) String() string {
 func (*CheckTokenRequest) ProtoMessage() {}
 
 func (x *CheckTokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[17]
+	mi := &file_youplus_service_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -984,7 +1197,7 @@ func (x *CheckTokenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CheckTokenRequest.ProtoReflect.Descriptor instead.
 func (*CheckTokenRequest) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{17}
+	return file_youplus_service_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *CheckTokenRequest) GetToken() string {
@@ -1009,7 +1222,7 @@ type CheckTokenReply struct {
 func (x *CheckTokenReply) Reset() {
 	*x = CheckTokenReply{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_youplus_service_proto_msgTypes[18]
+		mi := &file_youplus_service_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1022,7 +1235,7 @@ func (x *CheckTokenReply) String() string {
 func (*CheckTokenReply) ProtoMessage() {}
 
 func (x *CheckTokenReply) ProtoReflect() protoreflect.Message {
-	mi := &file_youplus_service_proto_msgTypes[18]
+	mi := &file_youplus_service_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1035,7 +1248,7 @@ func (x *CheckTokenReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CheckTokenReply.ProtoReflect.Descriptor instead.
 func (*CheckTokenReply) Descriptor() ([]byte, []int) {
-	return file_youplus_service_proto_rawDescGZIP(), []int{18}
+	return file_youplus_service_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *CheckTokenReply) GetSuccess() bool {
@@ -1083,151 +1296,185 @@ var file_youplus_service_proto_rawDesc = []byte{
 	0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x31, 0x0a, 0x11, 0x43,
 	0x68, 0x65, 0x63, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79,
 	0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20,
-	0x02, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x22, 0x2a,
-	0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01,
-	0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x2a, 0x0a, 0x14, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09,
-	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x4d, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x18, 0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09,
-	0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61,
-	0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61,
-	0x70, 0x73, 0x68, 0x6f, 0x74, 0x22, 0x4d, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53,
-	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
-	0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52,
-	0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x22, 0x4e, 0x0a, 0x16, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
-	0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
-	0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52,
-	0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x22, 0x53, 0x0a, 0x0b, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01,
-	0x20, 0x02, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a,
-	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72,
-	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x31, 0x0a, 0x15, 0x47, 0x65, 0x74,
-	0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20,
-	0x02, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x22, 0x1e, 0x0a, 0x08,
-	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x5a, 0x0a, 0x13,
-	0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x02, 0x28,
-	0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x2f, 0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73,
-	0x68, 0x6f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x79, 0x6f, 0x75,
-	0x70, 0x6c, 0x75, 0x73, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x09, 0x73,
-	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x22, 0x60, 0x0a, 0x14, 0x52, 0x65, 0x67, 0x69,
-	0x73, 0x74, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
-	0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
-	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x02, 0x28,
-	0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x34, 0x0a, 0x16, 0x55, 0x6e,
-	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
-	0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
-	0x22, 0x4a, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x45,
-	0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
-	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
-	0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x02,
-	0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x58, 0x0a, 0x10,
-	0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
-	0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
-	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x02, 0x28, 0x09, 0x52,
-	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x82, 0x01, 0x0a, 0x12, 0x47, 0x65, 0x6e, 0x65, 0x72,
-	0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a,
-	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x02, 0x28, 0x08, 0x52, 0x07,
-	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
-	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12,
-	0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x63,
-	0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x22, 0x4e, 0x0a, 0x14, 0x47,
-	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12,
-	0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x02, 0x28,
-	0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x29, 0x0a, 0x11, 0x43,
-	0x68, 0x65, 0x63, 0x6b, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52,
-	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x85, 0x01, 0x0a, 0x0f, 0x43, 0x68, 0x65, 0x63, 0x6b,
-	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75,
-	0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x02, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04,
-	0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65,
-	0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03,
-	0x75, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x32, 0xdc,
-	0x07, 0x0a, 0x0e, 0x59, 0x6f, 0x75, 0x50, 0x6c, 0x75, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x12, 0x4a, 0x0a, 0x0c, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65,
-	0x74, 0x12, 0x1c, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x68, 0x65, 0x63,
-	0x6b, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1a, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x44,
-	0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x50, 0x0a,
-	0x0e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12,
-	0x1e, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74,
-	0x61, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1c, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74,
-	0x61, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
-	0x46, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74,
-	0x12, 0x1d, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x1d, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c,
-	0x75, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75,
-	0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
-	0x48, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
-	0x74, 0x12, 0x1e, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x0e, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1e, 0x2e, 0x79, 0x6f,
-	0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79, 0x6f,
-	0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x0f, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44,
-	0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x1f, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73,
-	0x2e, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75,
-	0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
-	0x46, 0x0a, 0x0d, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x12, 0x1d, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73,
-	0x74, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x0f, 0x55, 0x6e, 0x72, 0x65, 0x67,
-	0x69, 0x73, 0x74, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1f, 0x2e, 0x79, 0x6f, 0x75,
-	0x70, 0x6c, 0x75, 0x73, 0x2e, 0x55, 0x6e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79, 0x6f,
-	0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x12, 0x4e, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x21, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c,
-	0x75, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x45, 0x78,
-	0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79, 0x6f,
-	0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x48, 0x65, 0x61, 0x72,
-	0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x19, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e,
-	0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x0d, 0x47, 0x65, 0x6e, 0x65,
-	0x72, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1d, 0x2e, 0x79, 0x6f, 0x75, 0x70,
-	0x6c, 0x75, 0x73, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65,
-	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c,
-	0x75, 0x73, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x0a, 0x43, 0x68, 0x65, 0x63, 0x6b,
-	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1a, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e,
+	0x02, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x22, 0xa4,
+	0x01, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x3e, 0x0a, 0x05, 0x70,
+	0x72, 0x6f, 0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x79, 0x6f, 0x75,
+	0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73,
+	0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x70, 0x73, 0x1a, 0x38, 0x0a, 0x0a, 0x50,
+	0x72, 0x6f, 0x70, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x2a, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x22, 0x4d, 0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61,
+	0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x22, 0x4d, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61,
+	0x73, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18,
+	0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x22,
+	0x4e, 0x0a, 0x16, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x73,
+	0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74,
+	0x61, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61,
+	0x73, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18,
+	0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x22,
+	0x83, 0x01, 0x0a, 0x13, 0x44, 0x69, 0x66, 0x66, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73,
+	0x65, 0x74, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x70, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x67,
+	0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70, 0x61, 0x67,
+	0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x6d, 0x0a, 0x11, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x44, 0x69, 0x66, 0x66, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x06, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03,
+	0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65,
+	0x77, 0x50, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x77,
+	0x50, 0x61, 0x74, 0x68, 0x22, 0x5f, 0x0a, 0x11, 0x44, 0x69, 0x66, 0x66, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x34, 0x0a, 0x07, 0x65, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x79, 0x6f, 0x75,
+	0x70, 0x6c, 0x75, 0x73, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x44, 0x69, 0x66,
+	0x66, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x02, 0x28, 0x03, 0x52, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x53, 0x0a, 0x0b, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18,
+	0x01, 0x20, 0x02, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x31, 0x0a, 0x15, 0x47, 0x65,
+	0x74, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x18, 0x01,
+	0x20, 0x02, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x22, 0x1e, 0x0a,
+	0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x5a, 0x0a,
+	0x13, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x02,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x2f, 0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x79, 0x6f,
+	0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x09,
+	0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x22, 0x60, 0x0a, 0x14, 0x52, 0x65, 0x67,
+	0x69, 0x73, 0x74, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x02,
+	0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x34, 0x0a, 0x16, 0x55,
+	0x6e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x22, 0x4a, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x02, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x58, 0x0a,
+	0x10, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x02, 0x28, 0x09,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x82, 0x01, 0x0a, 0x12, 0x47, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18,
+	0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x02, 0x28, 0x08, 0x52,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x22, 0x4e, 0x0a, 0x14,
+	0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x02,
+	0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x29, 0x0a, 0x11,
 	0x43, 0x68, 0x65, 0x63, 0x6b, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x18, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x68, 0x65, 0x63,
-	0x6b, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x06, 0x5a,
-	0x04, 0x2f, 0x72, 0x70, 0x63,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x02, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x85, 0x01, 0x0a, 0x0f, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x02, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x75, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x32,
+	0xa8, 0x08, 0x0a, 0x0e, 0x59, 0x6f, 0x75, 0x50, 0x6c, 0x75, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x4a, 0x0a, 0x0c, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x73,
+	0x65, 0x74, 0x12, 0x1c, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x68, 0x65,
+	0x63, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1a, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x50,
+	0x0a, 0x0e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f,
+	0x12, 0x1e, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61,
+	0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x61,
+	0x74, 0x61, 0x73, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x46, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65,
+	0x74, 0x12, 0x1d, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x1d, 0x2e, 0x79, 0x6f, 0x75, 0x70,
+	0x6c, 0x75, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c,
+	0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x48, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x12, 0x1e, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x0e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1e, 0x2e, 0x79,
+	0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79,
+	0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x0f, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x44, 0x61, 0x74, 0x61, 0x73, 0x65, 0x74, 0x12, 0x1f, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75,
+	0x73, 0x2e, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x73, 0x65,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c,
+	0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x4a, 0x0a, 0x0c, 0x44, 0x69, 0x66, 0x66, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x12, 0x1c, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x44, 0x69, 0x66, 0x66, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x44, 0x69, 0x66, 0x66, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0d,
+	0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1d, 0x2e,
+	0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79,
+	0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x0f, 0x55, 0x6e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1f, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75,
+	0x73, 0x2e, 0x55, 0x6e, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c,
+	0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x4e, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x45,
+	0x78, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x21, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x45, 0x78, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c,
+	0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x12, 0x43, 0x0a, 0x0e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65,
+	0x61, 0x74, 0x12, 0x19, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x48, 0x65, 0x61,
+	0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
+	0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x0d, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74,
+	0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1d, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73,
+	0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e,
+	0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x0a, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x1a, 0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x68, 0x65,
+	0x63, 0x6b, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18,
+	0x2e, 0x79, 0x6f, 0x75, 0x70, 0x6c, 0x75, 0x73, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x06, 0x5a, 0x04, 0x2f, 0x72,
+	0x70, 0x63,
 }
 
 var (
@@ -1242,7 +1489,7 @@ func file_youplus_service_proto_rawDescGZIP() []byte {
 	return file_youplus_service_proto_rawDescData
 }
 
-var file_youplus_service_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_youplus_service_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_youplus_service_proto_goTypes = []interface{}{
 	(*CheckDatasetRequest)(nil),      // 0: youplus.CheckDatasetRequest
 	(*CheckDatasetReply)(nil),        // 1: youplus.CheckDatasetReply
@@ -1251,52 +1498,60 @@ var file_youplus_service_proto_goTypes = []interface{}{
 	(*CreateSnapshotRequest)(nil),    // 4: youplus.CreateSnapshotRequest
 	(*DeleteSnapshotRequest)(nil),    // 5: youplus.DeleteSnapshotRequest
 	(*RollbackDatasetRequest)(nil),   // 6: youplus.RollbackDatasetRequest
-	(*ActionReply)(nil),              // 7: youplus.ActionReply
-	(*GetDatasetInfoRequest)(nil),    // 8: youplus.GetDatasetInfoRequest
-	(*Snapshot)(nil),                 // 9: youplus.Snapshot
-	(*GetDatasetInfoReply)(nil),      // 10: youplus.GetDatasetInfoReply
-	(*RegisterEntryRequest)(nil),     // 11: youplus.RegisterEntryRequest
-	(*UnregisterEntryRequest)(nil),   // 12: youplus.UnregisterEntryRequest
-	(*UpdateEntryExportRequest)(nil), // 13: youplus.UpdateEntryExportRequest
-	(*HeartbeatRequest)(nil),         // 14: youplus.HeartbeatRequest
-	(*GenerateTokenReply)(nil),       // 15: youplus.GenerateTokenReply
-	(*GenerateTokenRequest)(nil),     // 16: youplus.GenerateTokenRequest
-	(*CheckTokenRequest)(nil),        // 17: youplus.CheckTokenRequest
-	(*CheckTokenReply)(nil),          // 18: youplus.CheckTokenReply
+	(*DiffSnapshotRequest)(nil),      // 7: youplus.DiffSnapshotRequest
+	(*SnapshotDiffEntry)(nil),        // 8: youplus.SnapshotDiffEntry
+	(*DiffSnapshotReply)(nil),        // 9: youplus.DiffSnapshotReply
+	(*ActionReply)(nil),              // 10: youplus.ActionReply
+	(*GetDatasetInfoRequest)(nil),    // 11: youplus.GetDatasetInfoRequest
+	(*Snapshot)(nil),                 // 12: youplus.Snapshot
+	(*GetDatasetInfoReply)(nil),      // 13: youplus.GetDatasetInfoReply
+	(*RegisterEntryRequest)(nil),     // 14: youplus.RegisterEntryRequest
+	(*UnregisterEntryRequest)(nil),   // 15: youplus.UnregisterEntryRequest
+	(*UpdateEntryExportRequest)(nil), // 16: youplus.UpdateEntryExportRequest
+	(*HeartbeatRequest)(nil),         // 17: youplus.HeartbeatRequest
+	(*GenerateTokenReply)(nil),       // 18: youplus.GenerateTokenReply
+	(*GenerateTokenRequest)(nil),     // 19: youplus.GenerateTokenRequest
+	(*CheckTokenRequest)(nil),        // 20: youplus.CheckTokenRequest
+	(*CheckTokenReply)(nil),          // 21: youplus.CheckTokenReply
+	nil,                              // 22: youplus.CreateDatasetRequest.PropsEntry
 }
 var file_youplus_service_proto_depIdxs = []int32{
-	9,  // 0: youplus.GetDatasetInfoReply.snapshots:type_name -> youplus.Snapshot
-	0,  // 1: youplus.YouPlusService.CheckDataset:input_type -> youplus.CheckDatasetRequest
-	8,  // 2: youplus.YouPlusService.GetDatasetInfo:input_type -> youplus.GetDatasetInfoRequest
-	2,  // 3: youplus.YouPlusService.CreateDataset:input_type -> youplus.CreateDatasetRequest
-	3,  // 4: youplus.YouPlusService.DeleteDataset:input_type -> youplus.DeleteDatasetRequest
-	4,  // 5: youplus.YouPlusService.CreateSnapshot:input_type -> youplus.CreateSnapshotRequest
-	5,  // 6: youplus.YouPlusService.DeleteSnapshot:input_type -> youplus.DeleteSnapshotRequest
-	6,  // 7: youplus.YouPlusService.RollbackDataset:input_type -> youplus.RollbackDatasetRequest
-	11, // 8: youplus.YouPlusService.RegisterEntry:input_type -> youplus.RegisterEntryRequest
-	12, // 9: youplus.YouPlusService.UnregisterEntry:input_type -> youplus.UnregisterEntryRequest
-	13, // 10: youplus.YouPlusService.UpdateEntryExport:input_type -> youplus.UpdateEntryExportRequest
-	14, // 11: youplus.YouPlusService.EntryHeartbeat:input_type -> youplus.HeartbeatRequest
-	16, // 12: youplus.YouPlusService.GenerateToken:input_type -> youplus.GenerateTokenRequest
-	17, // 13: youplus.YouPlusService.CheckToken:input_type -> youplus.CheckTokenRequest
-	1,  // 14: youplus.YouPlusService.CheckDataset:output_type -> youplus.CheckDatasetReply
-	10, // 15: youplus.YouPlusService.GetDatasetInfo:output_type -> youplus.GetDatasetInfoReply
-	7,  // 16: youplus.YouPlusService.CreateDataset:output_type -> youplus.ActionReply
-	7,  // 17: youplus.YouPlusService.DeleteDataset:output_type -> youplus.ActionReply
-	7,  // 18: youplus.YouPlusService.CreateSnapshot:output_type -> youplus.ActionReply
-	7,  // 19: youplus.YouPlusService.DeleteSnapshot:output_type -> youplus.ActionReply
-	7,  // 20: youplus.YouPlusService.RollbackDataset:output_type -> youplus.ActionReply
-	7,  // 21: youplus.YouPlusService.RegisterEntry:output_type -> youplus.ActionReply
-	7,  // 22: youplus.YouPlusService.UnregisterEntry:output_type -> youplus.ActionReply
-	7,  // 23: youplus.YouPlusService.UpdateEntryExport:output_type -> youplus.ActionReply
-	7,  // 24: youplus.YouPlusService.EntryHeartbeat:output_type -> youplus.ActionReply
-	15, // 25: youplus.YouPlusService.GenerateToken:output_type -> youplus.GenerateTokenReply
-	18, // 26: youplus.YouPlusService.CheckToken:output_type -> youplus.CheckTokenReply
-	14, // [14:27] is the sub-list for method output_type
-	1,  // [1:14] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	22, // 0: youplus.CreateDatasetRequest.props:type_name -> youplus.CreateDatasetRequest.PropsEntry
+	8,  // 1: youplus.DiffSnapshotReply.entries:type_name -> youplus.SnapshotDiffEntry
+	12, // 2: youplus.GetDatasetInfoReply.snapshots:type_name -> youplus.Snapshot
+	0,  // 3: youplus.YouPlusService.CheckDataset:input_type -> youplus.CheckDatasetRequest
+	11, // 4: youplus.YouPlusService.GetDatasetInfo:input_type -> youplus.GetDatasetInfoRequest
+	2,  // 5: youplus.YouPlusService.CreateDataset:input_type -> youplus.CreateDatasetRequest
+	3,  // 6: youplus.YouPlusService.DeleteDataset:input_type -> youplus.DeleteDatasetRequest
+	4,  // 7: youplus.YouPlusService.CreateSnapshot:input_type -> youplus.CreateSnapshotRequest
+	5,  // 8: youplus.YouPlusService.DeleteSnapshot:input_type -> youplus.DeleteSnapshotRequest
+	6,  // 9: youplus.YouPlusService.RollbackDataset:input_type -> youplus.RollbackDatasetRequest
+	7,  // 10: youplus.YouPlusService.DiffSnapshot:input_type -> youplus.DiffSnapshotRequest
+	14, // 11: youplus.YouPlusService.RegisterEntry:input_type -> youplus.RegisterEntryRequest
+	15, // 12: youplus.YouPlusService.UnregisterEntry:input_type -> youplus.UnregisterEntryRequest
+	16, // 13: youplus.YouPlusService.UpdateEntryExport:input_type -> youplus.UpdateEntryExportRequest
+	17, // 14: youplus.YouPlusService.EntryHeartbeat:input_type -> youplus.HeartbeatRequest
+	19, // 15: youplus.YouPlusService.GenerateToken:input_type -> youplus.GenerateTokenRequest
+	20, // 16: youplus.YouPlusService.CheckToken:input_type -> youplus.CheckTokenRequest
+	1,  // 17: youplus.YouPlusService.CheckDataset:output_type -> youplus.CheckDatasetReply
+	13, // 18: youplus.YouPlusService.GetDatasetInfo:output_type -> youplus.GetDatasetInfoReply
+	10, // 19: youplus.YouPlusService.CreateDataset:output_type -> youplus.ActionReply
+	10, // 20: youplus.YouPlusService.DeleteDataset:output_type -> youplus.ActionReply
+	10, // 21: youplus.YouPlusService.CreateSnapshot:output_type -> youplus.ActionReply
+	10, // 22: youplus.YouPlusService.DeleteSnapshot:output_type -> youplus.ActionReply
+	10, // 23: youplus.YouPlusService.RollbackDataset:output_type -> youplus.ActionReply
+	9,  // 24: youplus.YouPlusService.DiffSnapshot:output_type -> youplus.DiffSnapshotReply
+	10, // 25: youplus.YouPlusService.RegisterEntry:output_type -> youplus.ActionReply
+	10, // 26: youplus.YouPlusService.UnregisterEntry:output_type -> youplus.ActionReply
+	10, // 27: youplus.YouPlusService.UpdateEntryExport:output_type -> youplus.ActionReply
+	10, // 28: youplus.YouPlusService.EntryHeartbeat:output_type -> youplus.ActionReply
+	18, // 29: youplus.YouPlusService.GenerateToken:output_type -> youplus.GenerateTokenReply
+	21, // 30: youplus.YouPlusService.CheckToken:output_type -> youplus.CheckTokenReply
+	17, // [17:31] is the sub-list for method output_type
+	3,  // [3:17] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_youplus_service_proto_init() }
@@ -1390,7 +1645,7 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ActionReply); i {
+			switch v := v.(*DiffSnapshotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1402,7 +1657,7 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetDatasetInfoRequest); i {
+			switch v := v.(*SnapshotDiffEntry); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1414,7 +1669,7 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Snapshot); i {
+			switch v := v.(*DiffSnapshotReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1426,7 +1681,7 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetDatasetInfoReply); i {
+			switch v := v.(*ActionReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1438,7 +1693,7 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RegisterEntryRequest); i {
+			switch v := v.(*GetDatasetInfoRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1450,7 +1705,7 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UnregisterEntryRequest); i {
+			switch v := v.(*Snapshot); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1462,7 +1717,7 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateEntryExportRequest); i {
+			switch v := v.(*GetDatasetInfoReply); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1474,7 +1729,7 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HeartbeatRequest); i {
+			switch v := v.(*RegisterEntryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1486,7 +1741,7 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GenerateTokenReply); i {
+			switch v := v.(*UnregisterEntryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1498,7 +1753,7 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GenerateTokenRequest); i {
+			switch v := v.(*UpdateEntryExportRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1510,7 +1765,7 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CheckTokenRequest); i {
+			switch v := v.(*HeartbeatRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1522,6 +1777,42 @@ func file_youplus_service_proto_init() {
 			}
 		}
 		file_youplus_service_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GenerateTokenReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_youplus_service_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GenerateTokenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_youplus_service_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckTokenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_youplus_service_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CheckTokenReply); i {
 			case 0:
 				return &v.state
@@ -1540,7 +1831,7 @@ func file_youplus_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_youplus_service_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   19,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
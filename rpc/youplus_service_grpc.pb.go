@@ -25,6 +25,7 @@ type YouPlusServiceClient interface {
 	CreateSnapshot(ctx context.Context, in *CreateSnapshotRequest, opts ...grpc.CallOption) (*ActionReply, error)
 	DeleteSnapshot(ctx context.Context, in *DeleteSnapshotRequest, opts ...grpc.CallOption) (*ActionReply, error)
 	RollbackDataset(ctx context.Context, in *RollbackDatasetRequest, opts ...grpc.CallOption) (*ActionReply, error)
+	DiffSnapshot(ctx context.Context, in *DiffSnapshotRequest, opts ...grpc.CallOption) (*DiffSnapshotReply, error)
 	RegisterEntry(ctx context.Context, in *RegisterEntryRequest, opts ...grpc.CallOption) (*ActionReply, error)
 	UnregisterEntry(ctx context.Context, in *UnregisterEntryRequest, opts ...grpc.CallOption) (*ActionReply, error)
 	UpdateEntryExport(ctx context.Context, in *UpdateEntryExportRequest, opts ...grpc.CallOption) (*ActionReply, error)
@@ -104,6 +105,15 @@ func (c *youPlusServiceClient) RollbackDataset(ctx context.Context, in *Rollback
 	return out, nil
 }
 
+func (c *youPlusServiceClient) DiffSnapshot(ctx context.Context, in *DiffSnapshotRequest, opts ...grpc.CallOption) (*DiffSnapshotReply, error) {
+	out := new(DiffSnapshotReply)
+	err := c.cc.Invoke(ctx, "/youplus.YouPlusService/DiffSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *youPlusServiceClient) RegisterEntry(ctx context.Context, in *RegisterEntryRequest, opts ...grpc.CallOption) (*ActionReply, error) {
 	out := new(ActionReply)
 	err := c.cc.Invoke(ctx, "/youplus.YouPlusService/RegisterEntry", in, out, opts...)
@@ -169,6 +179,7 @@ type YouPlusServiceServer interface {
 	CreateSnapshot(context.Context, *CreateSnapshotRequest) (*ActionReply, error)
 	DeleteSnapshot(context.Context, *DeleteSnapshotRequest) (*ActionReply, error)
 	RollbackDataset(context.Context, *RollbackDatasetRequest) (*ActionReply, error)
+	DiffSnapshot(context.Context, *DiffSnapshotRequest) (*DiffSnapshotReply, error)
 	RegisterEntry(context.Context, *RegisterEntryRequest) (*ActionReply, error)
 	UnregisterEntry(context.Context, *UnregisterEntryRequest) (*ActionReply, error)
 	UpdateEntryExport(context.Context, *UpdateEntryExportRequest) (*ActionReply, error)
@@ -203,6 +214,9 @@ func (UnimplementedYouPlusServiceServer) DeleteSnapshot(context.Context, *Delete
 func (UnimplementedYouPlusServiceServer) RollbackDataset(context.Context, *RollbackDatasetRequest) (*ActionReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RollbackDataset not implemented")
 }
+func (UnimplementedYouPlusServiceServer) DiffSnapshot(context.Context, *DiffSnapshotRequest) (*DiffSnapshotReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffSnapshot not implemented")
+}
 func (UnimplementedYouPlusServiceServer) RegisterEntry(context.Context, *RegisterEntryRequest) (*ActionReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterEntry not implemented")
 }
@@ -360,6 +374,24 @@ func _YouPlusService_RollbackDataset_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _YouPlusService_DiffSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YouPlusServiceServer).DiffSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/youplus.YouPlusService/DiffSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YouPlusServiceServer).DiffSnapshot(ctx, req.(*DiffSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _YouPlusService_RegisterEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RegisterEntryRequest)
 	if err := dec(in); err != nil {
@@ -503,6 +535,10 @@ var YouPlusService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RollbackDataset",
 			Handler:    _YouPlusService_RollbackDataset_Handler,
 		},
+		{
+			MethodName: "DiffSnapshot",
+			Handler:    _YouPlusService_DiffSnapshot_Handler,
+		},
 		{
 			MethodName: "RegisterEntry",
 			Handler:    _YouPlusService_RegisterEntry_Handler,
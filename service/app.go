@@ -441,6 +441,14 @@ func (p *TaskPool) NewInstallAppTask(packagePath string, callback InstallAppCall
 				arg.Value = realPath
 
 			}
+			if ulistArg.Type == "zvol" {
+				devicePath := GetVolumeDevicePath(arg.Value)
+				if !utils.IsFileExist(devicePath) {
+					task.OnError(errors.New("target zvol not found"))
+					return
+				}
+				arg.Value = devicePath
+			}
 		})
 		for _, cmdArg := range cmdArgs {
 			args = append(args, cmdArg.Key, cmdArg.Value)
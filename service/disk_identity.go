@@ -0,0 +1,192 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/projectxpolaris/youplus/utils"
+)
+
+var (
+	DiskByIdDir   = "/dev/disk/by-id"
+	DiskByUUIDDir = "/dev/disk/by-uuid"
+)
+
+// kernelDevicePattern match device path by kernel name, which may point to other disk after reboot
+var kernelDevicePattern = regexp.MustCompile(`^/dev/((?:sd|vd|hd|xvd)[a-z]+\d*|nvme\d+n\d+(?:p\d+)?|mmcblk\d+(?:p\d+)?)$`)
+
+// DeviceLinks is stable symlinks of block devices keyed by kernel name
+type DeviceLinks struct {
+	ById   map[string][]string
+	ByUUID map[string]string
+}
+
+// readLinkDir map kernel name to symlinks in udev link directory
+func readLinkDir(dir string) map[string][]string {
+	result := map[string][]string{}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return result
+	}
+	for _, entry := range entries {
+		linkPath := filepath.Join(dir, entry.Name())
+		realPath, err := filepath.EvalSymlinks(linkPath)
+		if err != nil {
+			continue
+		}
+		name := filepath.Base(realPath)
+		result[name] = append(result[name], linkPath)
+	}
+	return result
+}
+
+// idLinkPriority prefer world wide name, then links built from bus and serial
+func idLinkPriority(linkPath string) int {
+	name := filepath.Base(linkPath)
+	switch {
+	case strings.HasPrefix(name, "wwn-"):
+		return 0
+	case strings.HasPrefix(name, "ata-"), strings.HasPrefix(name, "nvme-"), strings.HasPrefix(name, "scsi-"):
+		return 1
+	}
+	return 2
+}
+
+func ReadDeviceLinks() *DeviceLinks {
+	links := &DeviceLinks{
+		ById:   readLinkDir(DiskByIdDir),
+		ByUUID: map[string]string{},
+	}
+	for _, paths := range links.ById {
+		sort.Slice(paths, func(i, j int) bool {
+			if idLinkPriority(paths[i]) != idLinkPriority(paths[j]) {
+				return idLinkPriority(paths[i]) < idLinkPriority(paths[j])
+			}
+			return paths[i] < paths[j]
+		})
+	}
+	for name, paths := range readLinkDir(DiskByUUIDDir) {
+		links.ByUUID[name] = paths[0]
+	}
+	return links
+}
+
+// StablePath return by-uuid path of formatted device, preferred by-id path otherwise, kernel path when device has no link
+func (l *DeviceLinks) StablePath(name string) string {
+	if path, ok := l.ByUUID[name]; ok {
+		return path
+	}
+	if paths := l.ById[name]; len(paths) > 0 {
+		return paths[0]
+	}
+	return diskDevicePath(name)
+}
+
+// ResolveDeviceName return kernel name of device given by kernel name, device path, by-id or by-uuid name, serial or wwn
+func ResolveDeviceName(identifier string) string {
+	if strings.HasPrefix(identifier, "/") {
+		if realPath, err := filepath.EvalSymlinks(identifier); err == nil {
+			return filepath.Base(realPath)
+		}
+		return filepath.Base(identifier)
+	}
+	if _, err := os.Stat(filepath.Join("/sys/class/block", identifier)); err == nil {
+		return identifier
+	}
+	for _, dir := range []string{DiskByIdDir, DiskByUUIDDir} {
+		if realPath, err := filepath.EvalSymlinks(filepath.Join(dir, identifier)); err == nil {
+			return filepath.Base(realPath)
+		}
+	}
+	for name, block := range utils.Lsblk() {
+		if block["type"] != "disk" {
+			continue
+		}
+		if (len(block["serial"]) > 0 && block["serial"] == identifier) || (len(block["wwn"]) > 0 && block["wwn"] == strings.ToLower(identifier)) {
+			return name
+		}
+	}
+	return identifier
+}
+
+// StableDevicePath convert device path to stable path, used as source of storage
+func StableDevicePath(devicePath string) string {
+	return ReadDeviceLinks().StablePath(ResolveDeviceName(devicePath))
+}
+
+// StableFstabSpec return fstab spec of device, filesystem uuid is used when device is formatted
+func StableFstabSpec(devicePath string) string {
+	name := ResolveDeviceName(devicePath)
+	if block, ok := utils.Lsblk()[name]; ok && len(block["uuid"]) > 0 {
+		return fmt.Sprintf("UUID=%s", block["uuid"])
+	}
+	return StableDevicePath(devicePath)
+}
+
+type FstabMigration struct {
+	File string `json:"file"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// fstabMigrationFlag is name of config item marking fstab migration is done
+const fstabMigrationFlag = "fstab_stable_spec_migrated"
+
+// MigrateFstabToStableSpec rewrite fstab entries and sources of part storages which use kernel device names,
+// only entries of part storages are touched and migration runs once
+func MigrateFstabToStableSpec() ([]FstabMigration, error) {
+	var count int64
+	err := database.Instance.Model(&database.ConfigItem{}).Where("name = ? and app_id = 0", fstabMigrationFlag).Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, nil
+	}
+	var storages []database.PartStorage
+	if err = database.Instance.Find(&storages).Error; err != nil {
+		return nil, err
+	}
+	migrations := make([]FstabMigration, 0)
+	for _, storage := range storages {
+		for _, mount := range DefaultFstab.Mounts {
+			if mount.File != storage.MountPoint || !kernelDevicePattern.MatchString(mount.Spec) {
+				continue
+			}
+			spec := StableFstabSpec(mount.Spec)
+			if spec == mount.Spec {
+				continue
+			}
+			migrations = append(migrations, FstabMigration{File: mount.File, From: mount.Spec, To: spec})
+			mount.Spec = spec
+		}
+	}
+	if len(migrations) > 0 {
+		if err = DefaultFstab.Save(); err != nil {
+			return nil, err
+		}
+	}
+	for _, storage := range storages {
+		if !kernelDevicePattern.MatchString(storage.Source) {
+			continue
+		}
+		source := StableDevicePath(storage.Source)
+		if source == storage.Source {
+			continue
+		}
+		err = database.Instance.Model(&database.PartStorage{}).Where("id = ?", storage.ID).Update("source", source).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = database.Instance.Create(&database.ConfigItem{Name: fstabMigrationFlag, Type: "flag", Key: "done"}).Error
+	if err != nil {
+		return nil, err
+	}
+	return migrations, nil
+}
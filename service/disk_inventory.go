@@ -0,0 +1,243 @@
+package service
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DiskEventAdd    = "add"
+	DiskEventRemove = "remove"
+	DiskEventChange = "change"
+)
+
+var DiskInventoryLogger = logrus.New().WithFields(logrus.Fields{
+	"scope": "DiskInventory",
+})
+
+type DiskEvent struct {
+	Action string `json:"action"`
+	Disk   *Disk  `json:"disk"`
+}
+
+// DiskEventListener receive disk events of one inventory refresh and storages whose status changed
+type DiskEventListener func(events []DiskEvent, storages map[string]string)
+
+// DiskInventory keep disk list up to date with kernel uevents, so disk list does not run lsblk on every request
+type DiskInventory struct {
+	Disks     []*Disk
+	Listeners []DiskEventListener
+	// debounce is time to collect burst of uevents, e.g. disk and all its partitions
+	debounce time.Duration
+	sync.RWMutex
+}
+
+var DefaultDiskInventory = DiskInventory{
+	Disks:    []*Disk{},
+	debounce: time.Second,
+}
+
+func (i *DiskInventory) AddListener(listener DiskEventListener) {
+	i.Lock()
+	defer i.Unlock()
+	i.Listeners = append(i.Listeners, listener)
+}
+
+func (i *DiskInventory) GetDisks() []*Disk {
+	i.RLock()
+	defer i.RUnlock()
+	return append([]*Disk{}, i.Disks...)
+}
+
+func diskChanged(before *Disk, after *Disk) bool {
+	if before.Size != after.Size || before.Serial != after.Serial || len(before.Parts) != len(after.Parts) {
+		return true
+	}
+	parts := map[string]*Part{}
+	for _, part := range before.Parts {
+		parts[part.Name] = part
+	}
+	for _, part := range after.Parts {
+		previous, ok := parts[part.Name]
+		if !ok || previous.Size != part.Size || previous.FSType != part.FSType || previous.MountPoint != part.MountPoint {
+			return true
+		}
+	}
+	return false
+}
+
+// Refresh read disk list and compare it with inventory, events are returned in add, change, remove order
+func (i *DiskInventory) Refresh() []DiskEvent {
+	disks := ReadDiskList()
+	i.Lock()
+	previous := map[string]*Disk{}
+	for _, disk := range i.Disks {
+		previous[disk.Name] = disk
+	}
+	i.Disks = disks
+	i.Unlock()
+	events := make([]DiskEvent, 0)
+	for _, disk := range disks {
+		before, ok := previous[disk.Name]
+		if !ok {
+			events = append(events, DiskEvent{Action: DiskEventAdd, Disk: disk})
+			continue
+		}
+		delete(previous, disk.Name)
+		if diskChanged(before, disk) {
+			events = append(events, DiskEvent{Action: DiskEventChange, Disk: disk})
+		}
+	}
+	for _, disk := range previous {
+		events = append(events, DiskEvent{Action: DiskEventRemove, Disk: disk})
+	}
+	return events
+}
+
+func (i *DiskInventory) refreshAndNotify() {
+	events := i.Refresh()
+	storages := DefaultStoragePool.RefreshStatus()
+	if len(events) == 0 && len(storages) == 0 {
+		return
+	}
+	for _, event := range events {
+		DiskInventoryLogger.WithField("disk", event.Disk.Name).Info("disk ", event.Action)
+	}
+	i.RLock()
+	listeners := append([]DiskEventListener{}, i.Listeners...)
+	i.RUnlock()
+	for _, listener := range listeners {
+		listener(events, storages)
+	}
+}
+
+func (i *DiskInventory) Run() {
+	i.Refresh()
+	DefaultStoragePool.RefreshStatus()
+	trigger := make(chan struct{}, 1)
+	go func() {
+		for range trigger {
+			// wait for burst of events and udev rules creating device links
+			<-time.After(i.debounce)
+			for len(trigger) > 0 {
+				<-trigger
+			}
+			_ = exec.Command("udevadm", "settle").Run()
+			i.refreshAndNotify()
+		}
+	}()
+	go func() {
+		// mount and umount emit no uevent, mountpoints of disk list are refreshed on mount table change
+		if err := watchMounts(trigger); err != nil {
+			DiskInventoryLogger.Error(err)
+		}
+		DiskInventoryLogger.Info("fall back to polling mount table")
+		for {
+			<-time.After(30 * time.Second)
+			select {
+			case trigger <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	go func() {
+		if err := listenBlockUevents(trigger); err != nil {
+			DiskInventoryLogger.Error(err)
+		}
+		// poll when uevent socket is not available
+		DiskInventoryLogger.Info("fall back to polling disk list")
+		for {
+			<-time.After(30 * time.Second)
+			i.refreshAndNotify()
+		}
+	}()
+}
+
+// listenBlockUevents read kernel uevents from netlink and trigger refresh on block device events
+func listenBlockUevents(trigger chan struct{}) error {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW, syscall.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(fd)
+	// group 1 is kernel uevent multicast group
+	if err = syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: 1}); err != nil {
+		return err
+	}
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if err == syscall.EINTR || err == syscall.ENOBUFS {
+				continue
+			}
+			return err
+		}
+		if isBlockDeviceUevent(buf[:n]) {
+			select {
+			case trigger <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// watchMounts trigger refresh when kernel report change of mount table by POLLPRI on mountinfo
+func watchMounts(trigger chan struct{}) error {
+	file, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	epfd, err := syscall.EpollCreate1(syscall.EPOLL_CLOEXEC)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(epfd)
+	fd := int(file.Fd())
+	event := syscall.EpollEvent{Events: syscall.EPOLLPRI | syscall.EPOLLERR, Fd: int32(fd)}
+	if err = syscall.EpollCtl(epfd, syscall.EPOLL_CTL_ADD, fd, &event); err != nil {
+		return err
+	}
+	events := make([]syscall.EpollEvent, 1)
+	for {
+		n, err := syscall.EpollWait(epfd, events, -1)
+		if err != nil {
+			if err == syscall.EINTR {
+				continue
+			}
+			return err
+		}
+		if n > 0 {
+			select {
+			case trigger <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// isBlockDeviceUevent parse uevent message, which is action@devpath followed by null separated KEY=VALUE
+func isBlockDeviceUevent(message []byte) bool {
+	env := map[string]string{}
+	for _, field := range bytes.Split(message, []byte{0}) {
+		if key, value, ok := strings.Cut(string(field), "="); ok {
+			env[key] = value
+		}
+	}
+	if env["SUBSYSTEM"] != "block" {
+		return false
+	}
+	switch env["ACTION"] {
+	case "add", "remove", "change":
+		return env["DEVTYPE"] == "disk" || env["DEVTYPE"] == "partition"
+	}
+	return false
+}
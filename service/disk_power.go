@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	DiskPowerStateActive  = "active"
+	DiskPowerStateIdle    = "idle"
+	DiskPowerStateStandby = "standby"
+	DiskPowerStateSleep   = "sleep"
+	DiskPowerStateUnknown = "unknown"
+)
+
+var InvalidPowerSettingError = errors.New("invalid disk power setting")
+
+var DiskPowerLogger = logrus.New().WithFields(logrus.Fields{
+	"scope": "DiskPower",
+})
+
+type DiskPowerOption struct {
+	Disk           string `json:"disk"`
+	APMLevel       int    `json:"apmLevel"`
+	StandbyTimeout int    `json:"standbyTimeout"`
+	WriteCache     string `json:"writeCache"`
+}
+
+// standbyTimeoutValue convert idle seconds to hdparm -S value, 1-240 are 5 seconds units and 241-251 are 30 minutes units
+func standbyTimeoutValue(seconds int) (int, error) {
+	switch {
+	case seconds == 0:
+		return 0, nil
+	case seconds < 0 || seconds > 330*60:
+		return 0, fmt.Errorf("%w: standby timeout must be between 0 and %d seconds", InvalidPowerSettingError, 330*60)
+	case seconds <= 1200:
+		return (seconds + 4) / 5, nil
+	}
+	return 240 + (seconds+1799)/1800, nil
+}
+
+func isNVMeDisk(name string) bool {
+	return strings.HasPrefix(name, "nvme")
+}
+
+// GetDiskPowerState read power state with hdparm -C, which does not wake disk in standby
+func GetDiskPowerState(name string) string {
+	if isNVMeDisk(name) {
+		return DiskPowerStateActive
+	}
+	out, err := exec.Command("hdparm", "-C", diskDevicePath(name)).Output()
+	if err != nil {
+		return DiskPowerStateUnknown
+	}
+	output := string(out)
+	switch {
+	case strings.Contains(output, "standby"):
+		return DiskPowerStateStandby
+	case strings.Contains(output, "sleeping"):
+		return DiskPowerStateSleep
+	case strings.Contains(output, "idle"):
+		return DiskPowerStateIdle
+	case strings.Contains(output, "active"):
+		return DiskPowerStateActive
+	}
+	return DiskPowerStateUnknown
+}
+
+// IsDiskSleeping report disk is spun down, monitors skip such disks so they are not woken up
+func IsDiskSleeping(name string) bool {
+	state := GetDiskPowerState(name)
+	return state == DiskPowerStateStandby || state == DiskPowerStateSleep
+}
+
+func GetDiskPowerSetting(disk *Disk) (*database.DiskPowerSetting, error) {
+	var setting database.DiskPowerSetting
+	err := database.Instance.Where("disk = ?", disk.StablePath).First(&setting).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+func applyDiskPowerSetting(name string, setting *database.DiskPowerSetting) error {
+	if isNVMeDisk(name) {
+		return nil
+	}
+	args := make([]string, 0)
+	if setting.APMLevel > 0 {
+		args = append(args, "-B", strconv.Itoa(setting.APMLevel))
+	}
+	standby, err := standbyTimeoutValue(setting.StandbyTimeout)
+	if err != nil {
+		return err
+	}
+	args = append(args, "-S", strconv.Itoa(standby))
+	switch setting.WriteCache {
+	case "on":
+		args = append(args, "-W", "1")
+	case "off":
+		args = append(args, "-W", "0")
+	}
+	args = append(args, diskDevicePath(name))
+	out, err := exec.Command("hdparm", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("hdparm failed: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// SetDiskPowerSetting save power setting of disk by its stable path and apply it
+func SetDiskPowerSetting(option DiskPowerOption) (*database.DiskPowerSetting, error) {
+	disk := GetDiskByName(option.Disk)
+	if disk == nil {
+		return nil, DiskNotFoundError
+	}
+	if option.APMLevel < 0 || option.APMLevel > 255 {
+		return nil, fmt.Errorf("%w: apm level must be between 1 and 255", InvalidPowerSettingError)
+	}
+	if option.WriteCache != "" && option.WriteCache != "on" && option.WriteCache != "off" {
+		return nil, fmt.Errorf("%w: write cache must be on or off", InvalidPowerSettingError)
+	}
+	if _, err := standbyTimeoutValue(option.StandbyTimeout); err != nil {
+		return nil, err
+	}
+	setting, err := GetDiskPowerSetting(disk)
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		setting = &database.DiskPowerSetting{Disk: disk.StablePath}
+	}
+	setting.APMLevel = option.APMLevel
+	setting.StandbyTimeout = option.StandbyTimeout
+	setting.WriteCache = option.WriteCache
+	if err = applyDiskPowerSetting(disk.Name, setting); err != nil {
+		return nil, err
+	}
+	if err = database.Instance.Save(setting).Error; err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+// ApplyDiskPowerSettings apply saved settings to attached disks at boot
+func ApplyDiskPowerSettings() {
+	var settings []database.DiskPowerSetting
+	if err := database.Instance.Find(&settings).Error; err != nil {
+		DiskPowerLogger.Error(err)
+		return
+	}
+	for idx := range settings {
+		setting := settings[idx]
+		disk := GetDiskByName(setting.Disk)
+		if disk == nil {
+			DiskPowerLogger.Warnf("disk %s not attached, skip power setting", setting.Disk)
+			continue
+		}
+		if err := applyDiskPowerSetting(disk.Name, &setting); err != nil {
+			DiskPowerLogger.WithField("disk", disk.Name).Error(err)
+		}
+	}
+}
+
+type DiskPowerStatus struct {
+	Disk    string                     `json:"disk"`
+	State   string                     `json:"state"`
+	Setting *database.DiskPowerSetting `json:"-"`
+}
+
+func GetDiskPowerStatus() ([]DiskPowerStatus, error) {
+	result := make([]DiskPowerStatus, 0)
+	for _, disk := range ReadDiskList() {
+		setting, err := GetDiskPowerSetting(disk)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, DiskPowerStatus{
+			Disk:    disk.Name,
+			State:   GetDiskPowerState(disk.Name),
+			Setting: setting,
+		})
+	}
+	return result, nil
+}
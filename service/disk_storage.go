@@ -88,11 +88,11 @@ func (s *DiskPartStorage) Remove() error {
 	return nil
 }
 func (s *DiskPartStorage) GetUsage() (used int64, free int64, err error) {
-	part := GetPartByName(s.Source)
+	part := GetPartByDevicePath(s.Source)
 	if part == nil {
 		return 0, 0, errors.New("unknown fs type")
 	}
-	stat, err := disk.Usage(s.Source)
+	stat, err := disk.Usage(s.MountPoint)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -103,13 +103,13 @@ func NewDiskPartStorage(source string) (Storage, error) {
 	id := xid.New().String()
 	storage := &DiskPartStorage{
 		Id:         id,
-		Source:     source,
+		Source:     StableDevicePath(source),
 		Name:       id,
 		MountPoint: fmt.Sprintf(filepath.Join("mnt", id)),
 	}
 	//read fstype
-	part := GetPartByName(filepath.Base(source))
-	if part == nil {
+	part := GetPartByDevicePath(source)
+	if part == nil || len(part.FSType) == 0 {
 		return nil, errors.New("unknown fs type")
 	}
 	//init mount dir
@@ -118,7 +118,7 @@ func NewDiskPartStorage(source string) (Storage, error) {
 		return nil, err
 	}
 	option := &AddMountOption{
-		Spec:    storage.Source,
+		Spec:    StableFstabSpec(source),
 		File:    storage.MountPoint,
 		VfsType: part.FSType,
 		MntOps: map[string]string{
@@ -0,0 +1,440 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	WipeMethodZero           = "zero"
+	WipeMethodRandom         = "random"
+	WipeMethodATASecureErase = "ata-secure-erase"
+	WipeMethodNVMeFormat     = "nvme-format"
+	WipeMethodNVMeSanitize   = "nvme-sanitize"
+)
+
+var (
+	UnsupportedWipeMethodError = errors.New("wipe method is not supported by disk")
+	WipeCanceledError          = errors.New("wipe canceled")
+)
+
+// wipeBlockSize is size of each write when filling disk, disk is synced every wipeSyncSize
+const (
+	wipeBlockSize = 4 << 20
+	wipeSyncSize  = 256 << 20
+)
+
+// nvmeSanitizeTimeout is how long sanitize status is polled before giving up
+const nvmeSanitizeTimeout = 24 * time.Hour
+
+var ataEraseTimePattern = regexp.MustCompile(`(\d+)min for SECURITY ERASE UNIT`)
+var nvmeNamespacePattern = regexp.MustCompile(`n\d+$`)
+
+type WipeExtra struct {
+	Device   string  `json:"device"`
+	Method   string  `json:"method"`
+	Total    uint64  `json:"total"`
+	Written  uint64  `json:"written"`
+	Progress float64 `json:"progress"`
+	// Speed is bytes per second, ETA is seconds left
+	Speed uint64 `json:"speed"`
+	ETA   int64  `json:"eta"`
+}
+
+type WipeCallback struct {
+	OnDone  func(task *WipeTask)
+	OnError func(task *WipeTask)
+}
+
+// WipeTask overwrite whole disk, fill methods can be canceled, firmware erase can not be stopped once started
+type WipeTask struct {
+	BaseTask
+	Extra    WipeExtra
+	Callback WipeCallback
+	started  time.Time
+	canceled int32
+}
+
+func (t *WipeTask) OnError(err error) {
+	t.SetError(err)
+	if t.Callback.OnError != nil {
+		t.Callback.OnError(t)
+	}
+	logrus.WithFields(logrus.Fields{
+		"device": t.Extra.Device,
+		"method": t.Extra.Method,
+	}).Error(err)
+}
+
+func (t *WipeTask) Cancel() error {
+	if t.Extra.Method != WipeMethodZero && t.Extra.Method != WipeMethodRandom {
+		return TaskNotCancelableError
+	}
+	atomic.StoreInt32(&t.canceled, 1)
+	return nil
+}
+
+func (t *WipeTask) isCanceled() bool {
+	return atomic.LoadInt32(&t.canceled) == 1
+}
+
+// updateProgress compute progress, speed and eta from progress ratio
+func (t *WipeTask) updateProgress(progress float64) {
+	if progress > 1 {
+		progress = 1
+	}
+	elapsed := time.Since(t.started).Seconds()
+	t.Extra.Progress = progress
+	t.Extra.Written = uint64(progress * float64(t.Extra.Total))
+	if elapsed > 0 {
+		t.Extra.Speed = uint64(float64(t.Extra.Written) / elapsed)
+	}
+	if progress > 0 {
+		t.Extra.ETA = int64(elapsed/progress - elapsed)
+	}
+	t.Updated = time.Now()
+}
+
+// fill write zero or random data to every byte of disk
+func (t *WipeTask) fill(random bool) error {
+	// exclusive open of block device fails with EBUSY when it is mounted or held by another device
+	file, err := os.OpenFile(t.Extra.Device, os.O_WRONLY|os.O_EXCL, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	buf := make([]byte, wipeBlockSize)
+	var stream cipher.Stream
+	if random {
+		// aes-ctr keystream of random key is much faster than reading from crypto/rand
+		key := make([]byte, 32)
+		iv := make([]byte, aes.BlockSize)
+		if _, err = rand.Read(key); err != nil {
+			return err
+		}
+		if _, err = rand.Read(iv); err != nil {
+			return err
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return err
+		}
+		stream = cipher.NewCTR(block, iv)
+	}
+	var written, unsynced uint64
+	for written < t.Extra.Total {
+		if t.isCanceled() {
+			return WipeCanceledError
+		}
+		chunk := buf
+		if left := t.Extra.Total - written; left < uint64(len(chunk)) {
+			chunk = chunk[:left]
+		}
+		if random {
+			for idx := range chunk {
+				chunk[idx] = 0
+			}
+			stream.XORKeyStream(chunk, chunk)
+		}
+		n, err := file.Write(chunk)
+		written += uint64(n)
+		unsynced += uint64(n)
+		if err != nil {
+			return err
+		}
+		if unsynced >= wipeSyncSize {
+			if err = file.Sync(); err != nil {
+				return err
+			}
+			unsynced = 0
+			t.updateProgress(float64(written) / float64(t.Extra.Total))
+		}
+	}
+	return file.Sync()
+}
+
+func ataSecureEraseMinutes(device string) (int, error) {
+	out, err := exec.Command("hdparm", "-I", device).Output()
+	if err != nil {
+		return 0, err
+	}
+	output := string(out)
+	index := strings.Index(output, "Security:")
+	if index < 0 {
+		return 0, UnsupportedWipeMethodError
+	}
+	security := output[index:]
+	supported, frozen := false, true
+	for i, line := range strings.Split(security, "\n") {
+		// section ends at next unindented header
+		if i > 0 && !strings.HasPrefix(line, "\t") {
+			break
+		}
+		switch strings.TrimSpace(line) {
+		case "supported":
+			supported = true
+		case "not\tfrozen":
+			frozen = false
+		}
+	}
+	if !supported || frozen {
+		return 0, fmt.Errorf("%w: security erase is not supported or disk is frozen", UnsupportedWipeMethodError)
+	}
+	minutes := 0
+	if match := ataEraseTimePattern.FindStringSubmatch(security); match != nil {
+		minutes, _ = strconv.Atoi(match[1])
+	}
+	return minutes, nil
+}
+
+// waitEstimated report progress from elapsed time until done is closed
+func (t *WipeTask) waitEstimated(estimate time.Duration, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(5 * time.Second):
+			if estimate > 0 {
+				progress := float64(time.Since(t.started)) / float64(estimate)
+				if progress > 0.99 {
+					progress = 0.99
+				}
+				t.updateProgress(progress)
+			}
+		}
+	}
+}
+
+func (t *WipeTask) ataSecureErase(minutes int) error {
+	// temporary password is cleared by drive when erase finishes
+	password := "youplus"
+	out, err := exec.Command("hdparm", "--user-master", "u", "--security-set-pass", password, t.Extra.Device).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("set security password failed: %s", strings.TrimSpace(string(out)))
+	}
+	done := make(chan struct{})
+	go t.waitEstimated(time.Duration(minutes)*time.Minute, done)
+	out, err = exec.Command("hdparm", "--user-master", "u", "--security-erase", password, t.Extra.Device).CombinedOutput()
+	close(done)
+	if err != nil {
+		// drive stays locked with temporary password when erase fails
+		eraseErr := fmt.Errorf("security erase failed: %s", strings.TrimSpace(string(out)))
+		out, err = exec.Command("hdparm", "--user-master", "u", "--security-disable", password, t.Extra.Device).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%v, disable security password failed, disk is locked with password %s: %s", eraseErr, password, strings.TrimSpace(string(out)))
+		}
+		return eraseErr
+	}
+	return nil
+}
+
+func (t *WipeTask) nvmeFormat() error {
+	done := make(chan struct{})
+	go t.waitEstimated(0, done)
+	out, err := exec.Command("nvme", "format", t.Extra.Device, "--ses=1", "--force").CombinedOutput()
+	close(done)
+	if err != nil {
+		return fmt.Errorf("nvme format failed: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// nvmeController return controller device of namespace, sanitize works on controller
+func nvmeController(device string) string {
+	return nvmeNamespacePattern.ReplaceAllString(device, "")
+}
+
+// checkNVMeNamespacesUnused make sure all namespaces of controller are unused, sanitize erase all of them
+func checkNVMeNamespacesUnused(device string) error {
+	controller := filepath.Base(nvmeController(device))
+	namespaces, err := filepath.Glob(filepath.Join("/sys/block", controller+"n*"))
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaces {
+		name := filepath.Base(namespace)
+		if name == filepath.Base(device) || !nvmeNamespacePattern.MatchString(name) {
+			continue
+		}
+		if _, err = CheckDiskUnused(name); err != nil {
+			return fmt.Errorf("namespace %s on same controller: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func nvmeJSON(args ...string) (map[string]interface{}, error) {
+	out, err := exec.Command("nvme", append(args, "-o", "json")...).Output()
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	if err = json.Unmarshal(out, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// findJSONKey return first value of key in nested json object
+func findJSONKey(value map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := value[key]; ok {
+		return v, true
+	}
+	for _, child := range value {
+		if m, ok := child.(map[string]interface{}); ok {
+			if v, ok := findJSONKey(m, key); ok {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// nvmeSanitizeAction return sanitize action supported by controller, block erase is preferred over crypto erase
+func nvmeSanitizeAction(device string) (string, error) {
+	ctrl, err := nvmeJSON("id-ctrl", nvmeController(device))
+	if err != nil {
+		return "", err
+	}
+	sanicap := jsonInt(ctrl["sanicap"])
+	switch {
+	case sanicap&0x2 != 0:
+		return "2", nil
+	case sanicap&0x1 != 0:
+		return "4", nil
+	}
+	return "", fmt.Errorf("%w: controller does not support sanitize", UnsupportedWipeMethodError)
+}
+
+func (t *WipeTask) nvmeSanitize(action string) error {
+	controller := nvmeController(t.Extra.Device)
+	out, err := exec.Command("nvme", "sanitize", controller, "--sanact="+action).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("nvme sanitize failed: %s", strings.TrimSpace(string(out)))
+	}
+	started := time.Now()
+	for time.Since(started) < nvmeSanitizeTimeout {
+		<-time.After(5 * time.Second)
+		log, err := nvmeJSON("sanitize-log", controller)
+		if err != nil {
+			return err
+		}
+		progress, _ := findJSONKey(log, "sprog")
+		status, _ := findJSONKey(log, "sstat")
+		// 0 is never sanitized, log may be read before sanitize started
+		switch jsonInt(status) & 0x7 {
+		case 0:
+			if time.Since(started) > time.Minute {
+				return errors.New("nvme sanitize did not start")
+			}
+		case 1, 4:
+			return nil
+		case 3:
+			return errors.New("nvme sanitize failed")
+		}
+		t.updateProgress(float64(jsonInt(progress)) / 65536)
+	}
+	return fmt.Errorf("nvme sanitize did not finish in %s", nvmeSanitizeTimeout)
+}
+
+type WipeDiskOption struct {
+	Device string `json:"device"`
+	Method string `json:"method"`
+}
+
+// NewWipeDiskTask overwrite all data on disk with given method
+func (p *TaskPool) NewWipeDiskTask(option WipeDiskOption, callback WipeCallback) (Task, error) {
+	disk, err := CheckDiskUnused(option.Device)
+	if err != nil {
+		return nil, err
+	}
+	total, _ := strconv.ParseUint(disk.Size, 10, 64)
+	devicePath := diskDevicePath(disk.Name)
+	var run func(task *WipeTask) error
+	switch option.Method {
+	case WipeMethodZero, WipeMethodRandom:
+		random := option.Method == WipeMethodRandom
+		run = func(task *WipeTask) error {
+			return task.fill(random)
+		}
+	case WipeMethodATASecureErase:
+		if isNVMeDisk(disk.Name) {
+			return nil, UnsupportedWipeMethodError
+		}
+		minutes, err := ataSecureEraseMinutes(devicePath)
+		if err != nil {
+			return nil, err
+		}
+		run = func(task *WipeTask) error {
+			return task.ataSecureErase(minutes)
+		}
+	case WipeMethodNVMeFormat:
+		if !isNVMeDisk(disk.Name) {
+			return nil, UnsupportedWipeMethodError
+		}
+		run = func(task *WipeTask) error {
+			return task.nvmeFormat()
+		}
+	case WipeMethodNVMeSanitize:
+		if !isNVMeDisk(disk.Name) {
+			return nil, UnsupportedWipeMethodError
+		}
+		if err = checkNVMeNamespacesUnused(devicePath); err != nil {
+			return nil, err
+		}
+		action, err := nvmeSanitizeAction(devicePath)
+		if err != nil {
+			return nil, err
+		}
+		run = func(task *WipeTask) error {
+			return task.nvmeSanitize(action)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", UnsupportedWipeMethodError, option.Method)
+	}
+	task := WipeTask{
+		BaseTask: NewBaseTask(),
+		Extra: WipeExtra{
+			Device: devicePath,
+			Method: option.Method,
+			Total:  total,
+		},
+		Callback: callback,
+		started:  time.Now(),
+	}
+	go func() {
+		err := run(&task)
+		if err == WipeCanceledError {
+			task.SetStatus(TaskStatusCancel)
+			return
+		}
+		if err != nil {
+			task.OnError(err)
+			return
+		}
+		task.updateProgress(1)
+		task.Extra.ETA = 0
+		task.SetStatus(TaskStatusDone)
+		if task.Callback.OnDone != nil {
+			task.Callback.OnDone(&task)
+		}
+	}()
+	p.Lock()
+	p.Tasks = append(p.Tasks, &task)
+	p.Unlock()
+	return &task, nil
+}
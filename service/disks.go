@@ -4,43 +4,65 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/projectxpolaris/youplus/utils"
 )
 
 type Disk struct {
-	Name  string  `json:"name,omitempty"`
-	Model string  `json:"model,omitempty"`
-	Size  string  `json:"size,omitempty"`
-	Parts []*Part `json:"parts,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	Model  string   `json:"model,omitempty"`
+	Size   string   `json:"size,omitempty"`
+	Serial string   `json:"serial,omitempty"`
+	WWN    string   `json:"wwn,omitempty"`
+	ById   []string `json:"byId,omitempty"`
+	// StablePath is device path which survives reboot and controller change
+	StablePath string  `json:"stablePath,omitempty"`
+	Parts      []*Part `json:"parts,omitempty"`
 }
 
 type Part struct {
-	Name       string `json:"name,omitempty"`
-	FSType     string `json:"fs_type,omitempty"`
-	Size       string `json:"size,omitempty"`
-	MountPoint string `json:"mountpoint,omitempty"`
+	Name       string   `json:"name,omitempty"`
+	FSType     string   `json:"fs_type,omitempty"`
+	Size       string   `json:"size,omitempty"`
+	MountPoint string   `json:"mountpoint,omitempty"`
+	UUID       string   `json:"uuid,omitempty"`
+	PartUUID   string   `json:"partuuid,omitempty"`
+	ById       []string `json:"byId,omitempty"`
+	ByUUID     string   `json:"byUuid,omitempty"`
+	StablePath string   `json:"stablePath,omitempty"`
 }
 
-func newPartFromRaw(block map[string]string) *Part {
-	return &Part{
+func newPartFromRaw(block map[string]string, links *DeviceLinks) *Part {
+	part := &Part{
 		Name:       block["name"],
 		FSType:     block["fstype"],
 		Size:       block["size"],
 		MountPoint: block["mountpoint"],
+		UUID:       block["uuid"],
+		PartUUID:   block["partuuid"],
+		ById:       links.ById[block["name"]],
+		ByUUID:     links.ByUUID[block["name"]],
 	}
+	part.StablePath = links.StablePath(part.Name)
+	return part
 }
 func ReadDiskList() []*Disk {
 	disks := utils.Lsblk()
+	links := ReadDeviceLinks()
 	result := make([]*Disk, 0)
 	for _, block := range disks {
 		if block["type"] == "disk" {
 			disk := &Disk{
-				Name:  block["name"],
-				Model: block["model"],
-				Size:  block["size"],
-				Parts: []*Part{},
+				Name:       block["name"],
+				Model:      block["model"],
+				Size:       block["size"],
+				Serial:     block["serial"],
+				WWN:        block["wwn"],
+				ById:       links.ById[block["name"]],
+				StablePath: links.StablePath(block["name"]),
+				Parts:      []*Part{},
 			}
 			result = append(result, disk)
 		}
@@ -49,14 +71,17 @@ func ReadDiskList() []*Disk {
 		if block["type"] == "part" {
 			for _, disk := range result {
 				if disk.Name == block["pkname"] {
-					disk.Parts = append(disk.Parts, newPartFromRaw(block))
+					disk.Parts = append(disk.Parts, newPartFromRaw(block, links))
 				}
 			}
 		}
 	}
 	return result
 }
+
+// GetDiskByName find disk by kernel name, device path, by-id name, serial or wwn
 func GetDiskByName(name string) *Disk {
+	name = ResolveDeviceName(name)
 	disks := ReadDiskList()
 	for _, disk := range disks {
 		if disk.Name == name {
@@ -65,11 +90,30 @@ func GetDiskByName(name string) *Disk {
 	}
 	return nil
 }
+
+// GetPartByName find partition by kernel name, device path, by-id or by-uuid name
 func GetPartByName(name string) *Part {
+	name = ResolveDeviceName(name)
 	disks := utils.Lsblk()
 	for _, block := range disks {
 		if block["type"] == "part" && block["name"] == name {
-			return newPartFromRaw(block)
+			return newPartFromRaw(block, ReadDeviceLinks())
+		}
+	}
+	return nil
+}
+
+// GetPartByDevicePath find partition or whole block device (e.g. zvol) by device path, symlinks are resolved
+func GetPartByDevicePath(devicePath string) *Part {
+	realPath, err := filepath.EvalSymlinks(devicePath)
+	if err != nil {
+		realPath = devicePath
+	}
+	name := filepath.Base(realPath)
+	disks := utils.Lsblk()
+	for _, block := range disks {
+		if (block["type"] == "part" || block["type"] == "disk" || isMDBlockType(block["type"])) && block["name"] == name {
+			return newPartFromRaw(block, ReadDeviceLinks())
 		}
 	}
 	return nil
@@ -25,6 +25,9 @@ func GetFileSystemWithUser(username string) (*YouPlusFileSystem, error) {
 	}
 	userFolders, err := GetUserShareList(&user)
 	for _, shareFolder := range userFolders {
+		if IsPathLocked(shareFolder.Folder.Path) {
+			continue
+		}
 		storageId := shareFolder.Folder.GetStorageId()
 		if len(storageId) > 0 {
 			userFileSystem.entities = append(userFileSystem.entities, FsEntity{
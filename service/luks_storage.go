@@ -0,0 +1,481 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/projectxpolaris/youplus/config"
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/rs/xid"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+var (
+	StorageLockedError      = errors.New("storage is locked")
+	StorageNotLUKSError     = errors.New("storage is not luks storage")
+	LastKeySlotError        = errors.New("can not remove last key slot")
+	KeySlotNotFoundError    = errors.New("key slot not found")
+	StorageAlreadyOpenError = errors.New("storage is already unlocked")
+)
+
+var LUKSLogger = logrus.New().WithFields(logrus.Fields{
+	"scope": "LUKS",
+})
+
+// LUKSMountRoot is where mapped devices of luks storages are mounted
+const LUKSMountRoot = "/mnt"
+
+var keySlotPattern = regexp.MustCompile(`^\s+(\d+): luks2`)
+
+// LUKSKey is passphrase or key file to open luks device, key file is used when both are given
+type LUKSKey struct {
+	Passphrase string `json:"passphrase"`
+	KeyFile    string `json:"keyFile"`
+}
+
+// LUKSKeyDir is directory key files of luks storages must be in
+func LUKSKeyDir() string {
+	return filepath.Join(config.ConfigDir(), "luks-keys")
+}
+
+// luksKeyFile check key file is in key directory after resolving symlinks and only readable by owner
+func luksKeyFile(keyFile string) (string, error) {
+	path := filepath.Clean(keyFile)
+	keyDir, err := filepath.EvalSymlinks(LUKSKeyDir())
+	if err != nil {
+		return "", fmt.Errorf("%w: key file must be in %s", InvalidKeyError, LUKSKeyDir())
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: key file %s not found", InvalidKeyError, keyFile)
+	}
+	if !filepath.IsAbs(path) || !strings.HasPrefix(realPath, keyDir+"/") {
+		return "", fmt.Errorf("%w: key file must be in %s", InvalidKeyError, LUKSKeyDir())
+	}
+	info, err := os.Stat(realPath)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() || info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("%w: key file must be regular file with 0600 permissions", InvalidKeyError)
+	}
+	return path, nil
+}
+
+// args return cryptsetup key arguments and stdin input
+func (k LUKSKey) args() ([]string, string, error) {
+	if len(k.KeyFile) > 0 {
+		path, err := luksKeyFile(k.KeyFile)
+		if err != nil {
+			return nil, "", err
+		}
+		return []string{"--key-file", path}, "", nil
+	}
+	if len(k.Passphrase) < 8 || len(k.Passphrase) > 512 {
+		return nil, "", fmt.Errorf("%w: passphrase must be 8 to 512 characters", InvalidKeyError)
+	}
+	return []string{"--key-file", "-"}, k.Passphrase, nil
+}
+
+func runCryptsetup(input string, args ...string) (string, error) {
+	cmd := exec.Command("cryptsetup", args...)
+	cmd.Stdin = strings.NewReader(input)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		message := strings.TrimSpace(string(out))
+		if len(message) == 0 {
+			return "", err
+		}
+		return "", errors.New(message)
+	}
+	return string(out), nil
+}
+
+// LUKSPartStorage is partition encrypted with luks2, mapped device is mounted only when unlocked
+type LUKSPartStorage struct {
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Source     string   `json:"source"`
+	MapperName string   `json:"mapper_name"`
+	MountPoint string   `json:"mount_point"`
+	FSType     string   `json:"fs_type"`
+	KeyFile    string   `json:"-"`
+	AutoUnlock bool     `json:"auto_unlock"`
+	Fs         afero.Fs `json:"-"`
+}
+
+func (s *LUKSPartStorage) GetId() string {
+	return s.Id
+}
+
+func (s *LUKSPartStorage) GetName() string {
+	return s.Name
+}
+
+func (s *LUKSPartStorage) GetRootPath() string {
+	return s.MountPoint
+}
+
+func (s *LUKSPartStorage) GetFS() afero.Fs {
+	return s.Fs
+}
+
+func (s *LUKSPartStorage) MapperPath() string {
+	return filepath.Join("/dev/mapper", s.MapperName)
+}
+
+// IsLocked report mapped device is not open
+func (s *LUKSPartStorage) IsLocked() bool {
+	_, err := os.Stat(s.MapperPath())
+	return err != nil
+}
+
+func (s *LUKSPartStorage) LoadFromSave(data *database.LUKSStorage) {
+	s.Id = data.ID
+	s.Name = data.Name
+	s.Source = data.Source
+	s.MapperName = data.MapperName
+	s.MountPoint = data.MountPoint
+	s.FSType = data.FSType
+	s.KeyFile = data.KeyFile
+	s.AutoUnlock = data.AutoUnlock
+	s.Fs = afero.NewBasePathFs(afero.NewOsFs(), s.MountPoint)
+}
+
+func (s *LUKSPartStorage) SaveData() error {
+	rawData := map[string]interface{}{}
+	rawData["name"] = s.Name
+	rawData["source"] = s.Source
+	rawData["mount_point"] = s.MountPoint
+	rawData["key_file"] = s.KeyFile
+	rawData["auto_unlock"] = s.AutoUnlock
+	return database.Instance.Model(&database.LUKSStorage{}).Where("id = ?", s.Id).Updates(rawData).Error
+}
+
+func (s *LUKSPartStorage) Update(option StorageUpdateOption) error {
+	if option.Name == "" {
+		return nil
+	}
+	err := database.Instance.Model(&database.LUKSStorage{}).Where("id = ?", s.Id).Update("name", option.Name).Error
+	if err != nil {
+		return err
+	}
+	s.Name = option.Name
+	return nil
+}
+
+func (s *LUKSPartStorage) GetUsage() (used int64, free int64, err error) {
+	if s.IsLocked() {
+		return 0, 0, StorageLockedError
+	}
+	stat, err := disk.Usage(s.MountPoint)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int64(stat.Used), int64(stat.Total), nil
+}
+
+// Remove lock storage and forget it, encrypted data on partition is kept
+func (s *LUKSPartStorage) Remove() error {
+	if !s.IsLocked() {
+		if err := s.Lock(); err != nil {
+			return err
+		}
+	}
+	if err := database.Instance.Unscoped().Where("id = ?", s.Id).Delete(&database.LUKSStorage{}).Error; err != nil {
+		return err
+	}
+	return os.Remove(s.MountPoint)
+}
+
+func (s *LUKSPartStorage) open(key LUKSKey) error {
+	args, input, err := key.args()
+	if err != nil {
+		return err
+	}
+	args = append([]string{"open", "--type", "luks2"}, args...)
+	_, err = runCryptsetup(input, append(args, s.Source, s.MapperName)...)
+	return err
+}
+
+func (s *LUKSPartStorage) mount() error {
+	if err := os.MkdirAll(s.MountPoint, os.ModePerm); err != nil {
+		return err
+	}
+	out, err := exec.Command("mount", "-t", s.FSType, s.MapperPath(), s.MountPoint).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("mount failed: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Unlock open luks device and mount it, share folders on storage are enabled again
+func (s *LUKSPartStorage) Unlock(key LUKSKey) error {
+	if !s.IsLocked() {
+		return StorageAlreadyOpenError
+	}
+	if err := s.open(key); err != nil {
+		return err
+	}
+	if err := s.mount(); err != nil {
+		_, _ = runCryptsetup("", "close", s.MapperName)
+		return err
+	}
+	syncShareFoldersOfPath(s.MountPoint)
+	DefaultStoragePool.RefreshStatus()
+	return nil
+}
+
+// Lock unmount storage and close luks device, share folders on storage are disabled in smb
+func (s *LUKSPartStorage) Lock() error {
+	if s.IsLocked() {
+		return nil
+	}
+	out, err := exec.Command("umount", s.MountPoint).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("umount failed: %s", strings.TrimSpace(string(out)))
+	}
+	if _, err = runCryptsetup("", "close", s.MapperName); err != nil {
+		return err
+	}
+	syncShareFoldersOfPath(s.MountPoint)
+	DefaultStoragePool.RefreshStatus()
+	return nil
+}
+
+// ListKeySlots return used key slots of luks header
+func (s *LUKSPartStorage) ListKeySlots() ([]int, error) {
+	out, err := runCryptsetup("", "luksDump", s.Source)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]int, 0)
+	inKeyslots := false
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			inKeyslots = strings.HasPrefix(line, "Keyslots:")
+			continue
+		}
+		if !inKeyslots {
+			continue
+		}
+		if match := keySlotPattern.FindStringSubmatch(line); match != nil {
+			slot, _ := strconv.Atoi(match[1])
+			slots = append(slots, slot)
+		}
+	}
+	return slots, nil
+}
+
+// AddKey add new key to free slot, existing key is required to authorize it
+func (s *LUKSPartStorage) AddKey(key LUKSKey, newKey LUKSKey) error {
+	args, input, err := key.args()
+	if err != nil {
+		return err
+	}
+	if _, _, err = newKey.args(); err != nil {
+		return err
+	}
+	newKeyFile := newKey.KeyFile
+	if len(newKeyFile) == 0 {
+		// new passphrase is passed by temporary file, stdin is used by existing key
+		if err = os.MkdirAll(LUKSKeyDir(), 0700); err != nil {
+			return err
+		}
+		file, err := os.CreateTemp(LUKSKeyDir(), ".key-")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(file.Name())
+		_, err = file.WriteString(newKey.Passphrase)
+		file.Close()
+		if err != nil {
+			return err
+		}
+		newKeyFile = file.Name()
+	}
+	args = append([]string{"luksAddKey", "--batch-mode"}, args...)
+	_, err = runCryptsetup(input, append(args, s.Source, newKeyFile)...)
+	return err
+}
+
+// RemoveKey wipe key slot, one of remaining keys is required and the last slot can not be removed
+func (s *LUKSPartStorage) RemoveKey(slot int, key LUKSKey) error {
+	slots, err := s.ListKeySlots()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, used := range slots {
+		found = found || used == slot
+	}
+	if !found {
+		return KeySlotNotFoundError
+	}
+	if len(slots) <= 1 {
+		return LastKeySlotError
+	}
+	args, input, err := key.args()
+	if err != nil {
+		return err
+	}
+	args = append([]string{"luksKillSlot", "--batch-mode"}, args...)
+	_, err = runCryptsetup(input, append(args, s.Source, strconv.Itoa(slot))...)
+	return err
+}
+
+// SetAutoUnlock unlock storage with key file at boot, key file must be in key directory and open device
+func (s *LUKSPartStorage) SetAutoUnlock(keyFile string) error {
+	path, err := luksKeyFile(keyFile)
+	if err != nil {
+		return err
+	}
+	args := []string{"--key-file", path}
+	args = append([]string{"open", "--test-passphrase"}, args...)
+	if _, err = runCryptsetup("", append(args, s.Source)...); err != nil {
+		return fmt.Errorf("%w: %s", InvalidKeyError, err.Error())
+	}
+	s.KeyFile = path
+	s.AutoUnlock = true
+	return s.SaveData()
+}
+
+func (s *LUKSPartStorage) RemoveAutoUnlock() error {
+	s.KeyFile = ""
+	s.AutoUnlock = false
+	return s.SaveData()
+}
+
+type LUKSStorageOption struct {
+	LUKSKey
+	Source     string `json:"source"`
+	Format     string `json:"format"`
+	AutoUnlock bool   `json:"autoUnlock"`
+}
+
+// NewLUKSPartStorage format partition with luks2 and filesystem, then mount mapped device
+func NewLUKSPartStorage(option LUKSStorageOption) (*LUKSPartStorage, error) {
+	if _, err := CheckPartitionAvailable(option.Source); err != nil {
+		return nil, err
+	}
+	format := option.Format
+	if len(format) == 0 {
+		format = "ext4"
+	}
+	definition, ok := PartitionFormatMapping[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported filesystem %s", InvalidPartitionOptionError, format)
+	}
+	keyArgs, input, err := option.LUKSKey.args()
+	if err != nil {
+		return nil, err
+	}
+	if option.AutoUnlock && len(option.KeyFile) == 0 {
+		return nil, fmt.Errorf("%w: auto unlock requires key file", InvalidKeyError)
+	}
+	devicePath := diskDevicePath(ResolveDeviceName(option.Source))
+	id := xid.New().String()
+	storage := &LUKSPartStorage{
+		Id:         id,
+		Name:       id,
+		Source:     StableDevicePath(devicePath),
+		MapperName: "luks-" + id,
+		MountPoint: filepath.Join(LUKSMountRoot, id),
+		FSType:     format,
+		AutoUnlock: option.AutoUnlock,
+		Fs:         afero.NewBasePathFs(afero.NewOsFs(), filepath.Join(LUKSMountRoot, id)),
+	}
+	if option.AutoUnlock {
+		// key file argument is cleaned path checked by args
+		storage.KeyFile = keyArgs[1]
+	}
+	args := append([]string{"luksFormat", "--type", "luks2", "--batch-mode"}, keyArgs...)
+	if _, err = runCryptsetup(input, append(args, devicePath)...); err != nil {
+		return nil, err
+	}
+	if err = storage.open(option.LUKSKey); err != nil {
+		return nil, err
+	}
+	mkfsArgs := append(append([]string{}, definition.Args...), storage.MapperPath())
+	if out, err := exec.Command(definition.Command, mkfsArgs...).CombinedOutput(); err != nil {
+		_, _ = runCryptsetup("", "close", storage.MapperName)
+		return nil, fmt.Errorf("format failed: %s", strings.TrimSpace(string(out)))
+	}
+	if err = storage.mount(); err != nil {
+		_, _ = runCryptsetup("", "close", storage.MapperName)
+		return nil, err
+	}
+	err = database.Instance.Create(&database.LUKSStorage{
+		ID:         id,
+		MountPoint: storage.MountPoint,
+		Name:       storage.Name,
+		Source:     storage.Source,
+		MapperName: storage.MapperName,
+		FSType:     storage.FSType,
+		KeyFile:    storage.KeyFile,
+		AutoUnlock: storage.AutoUnlock,
+	}).Error
+	if err != nil {
+		// storage is not registered, leave device closed like before
+		if lockErr := storage.Lock(); lockErr != nil {
+			LUKSLogger.Error(lockErr)
+		} else {
+			_ = os.Remove(storage.MountPoint)
+		}
+		return nil, err
+	}
+	return storage, nil
+}
+
+// GetLUKSStorage return luks storage by id
+func GetLUKSStorage(id string) (*LUKSPartStorage, error) {
+	storage := DefaultStoragePool.GetStorageById(id)
+	if storage == nil {
+		return nil, StorageNotFoundError
+	}
+	luksStorage, ok := storage.(*LUKSPartStorage)
+	if !ok {
+		return nil, StorageNotLUKSError
+	}
+	return luksStorage, nil
+}
+
+// luksStorageOfPath return luks storage which contains path
+func luksStorageOfPath(path string) *LUKSPartStorage {
+	for _, storage := range DefaultStoragePool.List() {
+		luksStorage, ok := storage.(*LUKSPartStorage)
+		if !ok {
+			continue
+		}
+		if path == luksStorage.MountPoint || strings.HasPrefix(path, luksStorage.MountPoint+"/") {
+			return luksStorage
+		}
+	}
+	return nil
+}
+
+// UnlockLUKSStoragesAtBoot unlock luks storages configured with key file
+func UnlockLUKSStoragesAtBoot() {
+	for _, storage := range DefaultStoragePool.List() {
+		luksStorage, ok := storage.(*LUKSPartStorage)
+		if !ok || !luksStorage.AutoUnlock || !luksStorage.IsLocked() {
+			continue
+		}
+		if err := luksStorage.Unlock(LUKSKey{KeyFile: luksStorage.KeyFile}); err != nil {
+			LUKSLogger.WithField("storage", luksStorage.Id).Error(err)
+			continue
+		}
+		LUKSLogger.WithField("storage", luksStorage.Id).Info("storage unlocked")
+	}
+}
@@ -0,0 +1,535 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/projectxpolaris/youplus/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// MDEventClassPrefix is prefix of event class created from mdadm monitor events, e.g. youplus.md.Fail
+const MDEventClassPrefix = "youplus.md."
+
+const (
+	MDStatPath = "/proc/mdstat"
+	// MDAdmConfPath is used when none of MDAdmConfPaths exists
+	MDAdmConfPath = "/etc/mdadm/mdadm.conf"
+)
+
+var MDAdmConfPaths = []string{"/etc/mdadm/mdadm.conf", "/etc/mdadm.conf"}
+
+var (
+	MDArrayNotFoundError      = errors.New("raid array not found")
+	MDMemberNotFoundError     = errors.New("device is not member of raid array")
+	InvalidMDArrayOptionError = errors.New("invalid raid array option")
+	MDArrayRedundancyError    = errors.New("raid array has no redundancy left")
+)
+
+var MDLogger = logrus.New().WithFields(logrus.Fields{
+	"scope": "MD",
+})
+
+// MDLevelMinDevices is supported raid levels and their minimum number of active devices
+var MDLevelMinDevices = map[string]int{
+	"0":  2,
+	"1":  2,
+	"5":  3,
+	"6":  4,
+	"10": 4,
+}
+
+var mdArrayNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+var mdStatArrayPattern = regexp.MustCompile(`^(md\S+) : `)
+
+// mdMemberPattern match member in mdstat line, e.g. sdb1[0] or sdc[2](F)
+var mdMemberPattern = regexp.MustCompile(`^(\S+)\[\d+\]`)
+
+type MDMember struct {
+	Device string `json:"device"`
+	// Slot is role in array, -1 for spare or faulty device
+	Slot  int    `json:"slot"`
+	State string `json:"state"`
+}
+
+type MDArray struct {
+	Name       string `json:"name"`
+	Device     string `json:"device"`
+	ArrayName  string `json:"arrayName"`
+	UUID       string `json:"uuid"`
+	Level      string `json:"level"`
+	State      string `json:"state"`
+	Size       uint64 `json:"size"`
+	RaidDisks  int    `json:"raidDisks"`
+	Degraded   int    `json:"degraded"`
+	SyncAction string `json:"syncAction"`
+	// SyncProgress is progress of resync, recovery, check or reshape from 0 to 1
+	SyncProgress float64    `json:"syncProgress"`
+	Members      []MDMember `json:"members"`
+}
+
+func isMDBlockType(blockType string) bool {
+	return strings.HasPrefix(blockType, "raid") || blockType == "linear"
+}
+
+func readMDAttr(name string, attr string) string {
+	raw, err := os.ReadFile(filepath.Join("/sys/block", name, "md", attr))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// readMDStat return array names and their member device names
+func readMDStat() (map[string][]string, error) {
+	file, err := os.Open(MDStatPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	arrays := map[string][]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		match := mdStatArrayPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		members := make([]string, 0)
+		for _, field := range strings.Fields(line) {
+			if member := mdMemberPattern.FindStringSubmatch(field); member != nil {
+				members = append(members, member[1])
+			}
+		}
+		arrays[match[1]] = members
+	}
+	return arrays, scanner.Err()
+}
+
+// mdMemberOf return name of md array which contains any of block device names
+func mdMemberOf(names map[string]bool) string {
+	arrays, err := readMDStat()
+	if err != nil {
+		return ""
+	}
+	for array, members := range arrays {
+		for _, member := range members {
+			if names[member] {
+				return array
+			}
+		}
+	}
+	return ""
+}
+
+// mdDetailExport return key value output of mdadm --detail --export
+func mdDetailExport(device string) map[string]string {
+	result := map[string]string{}
+	out, err := exec.Command("mdadm", "--detail", "--export", device).Output()
+	if err != nil {
+		return result
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// readMDArray read array state from sysfs, progress of sync_completed is in sectors
+func readMDArray(name string, members []string) *MDArray {
+	device := diskDevicePath(name)
+	detail := mdDetailExport(device)
+	array := &MDArray{
+		Name:       name,
+		Device:     device,
+		ArrayName:  detail["MD_DEVNAME"],
+		UUID:       detail["MD_UUID"],
+		Level:      strings.TrimPrefix(readMDAttr(name, "level"), "raid"),
+		State:      readMDAttr(name, "array_state"),
+		SyncAction: readMDAttr(name, "sync_action"),
+		Members:    []MDMember{},
+	}
+	if raw, err := os.ReadFile(filepath.Join("/sys/block", name, "size")); err == nil {
+		sectors, _ := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+		array.Size = sectors * 512
+	}
+	array.RaidDisks, _ = strconv.Atoi(readMDAttr(name, "raid_disks"))
+	array.Degraded, _ = strconv.Atoi(readMDAttr(name, "degraded"))
+	if done, total, ok := strings.Cut(readMDAttr(name, "sync_completed"), " / "); ok {
+		doneSectors, _ := strconv.ParseFloat(done, 64)
+		totalSectors, _ := strconv.ParseFloat(total, 64)
+		if totalSectors > 0 {
+			array.SyncProgress = doneSectors / totalSectors
+		}
+	}
+	for _, member := range members {
+		slot, err := strconv.Atoi(readMDAttr(name, filepath.Join("dev-"+member, "slot")))
+		if err != nil {
+			slot = -1
+		}
+		array.Members = append(array.Members, MDMember{
+			Device: diskDevicePath(member),
+			Slot:   slot,
+			State:  readMDAttr(name, filepath.Join("dev-"+member, "state")),
+		})
+	}
+	return array
+}
+
+func GetMDArrays() ([]*MDArray, error) {
+	arrays, err := readMDStat()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*MDArray, 0)
+	for name, members := range arrays {
+		result = append(result, readMDArray(name, members))
+	}
+	return result, nil
+}
+
+// GetMDArray return array by kernel name, device path or array name
+func GetMDArray(identifier string) (*MDArray, error) {
+	name := ResolveDeviceName(identifier)
+	if !strings.HasPrefix(name, "md") {
+		name = ResolveDeviceName(filepath.Join("/dev/md", identifier))
+	}
+	arrays, err := readMDStat()
+	if err != nil {
+		return nil, err
+	}
+	members, ok := arrays[name]
+	if !ok {
+		return nil, MDArrayNotFoundError
+	}
+	return readMDArray(name, members), nil
+}
+
+func runMdadm(args ...string) error {
+	out, err := exec.Command("mdadm", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("mdadm failed: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// SaveMDAdmConfig replace ARRAY lines of mdadm.conf with current arrays, so they are assembled at boot
+func SaveMDAdmConfig() error {
+	configPath := MDAdmConfPath
+	for _, path := range MDAdmConfPaths {
+		if _, err := os.Stat(path); err == nil {
+			configPath = path
+			break
+		}
+	}
+	out, err := exec.Command("mdadm", "--detail", "--scan").Output()
+	if err != nil {
+		return err
+	}
+	lines := make([]string, 0)
+	if raw, err := os.ReadFile(configPath); err == nil {
+		// indented lines continue previous ARRAY line
+		inArray := false
+		for _, line := range strings.Split(strings.TrimRight(string(raw), "\n"), "\n") {
+			if strings.HasPrefix(line, "ARRAY ") || (inArray && strings.HasPrefix(line, " ")) {
+				inArray = true
+				continue
+			}
+			inArray = false
+			lines = append(lines, line)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if err = os.MkdirAll(filepath.Dir(configPath), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+type MDArrayOption struct {
+	Name    string   `json:"name"`
+	Level   string   `json:"level"`
+	Devices []string `json:"devices"`
+	Spares  []string `json:"spares"`
+}
+
+// CreateMDArray create array from unused disks, initial sync runs in background
+func CreateMDArray(option MDArrayOption) (*MDArray, error) {
+	if !mdArrayNamePattern.MatchString(option.Name) {
+		return nil, fmt.Errorf("%w: name must contain only letters, digits, - and _", InvalidMDArrayOptionError)
+	}
+	minDevices, ok := MDLevelMinDevices[option.Level]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported level %s", InvalidMDArrayOptionError, option.Level)
+	}
+	if len(option.Devices) < minDevices {
+		return nil, fmt.Errorf("%w: raid%s requires at least %d devices", InvalidMDArrayOptionError, option.Level, minDevices)
+	}
+	if option.Level == "0" && len(option.Spares) > 0 {
+		return nil, fmt.Errorf("%w: raid0 can not have spares", InvalidMDArrayOptionError)
+	}
+	if _, err := os.Stat(filepath.Join("/dev/md", option.Name)); err == nil {
+		return nil, fmt.Errorf("%w: array %s already exists", InvalidMDArrayOptionError, option.Name)
+	}
+	seen := map[string]bool{}
+	devicePaths := make([]string, 0)
+	for _, device := range append(append([]string{}, option.Devices...), option.Spares...) {
+		disk, err := CheckDiskUnused(device)
+		if err != nil {
+			return nil, err
+		}
+		if seen[disk.Name] {
+			return nil, fmt.Errorf("%w: device %s is given twice", InvalidMDArrayOptionError, disk.Name)
+		}
+		seen[disk.Name] = true
+		devicePaths = append(devicePaths, diskDevicePath(disk.Name))
+	}
+	args := []string{
+		"--create", filepath.Join("/dev/md", option.Name),
+		"--run",
+		"--metadata=1.2",
+		"--name=" + option.Name,
+		"--level=" + option.Level,
+		"--raid-devices=" + strconv.Itoa(len(option.Devices)),
+	}
+	if len(option.Spares) > 0 {
+		args = append(args, "--spare-devices="+strconv.Itoa(len(option.Spares)))
+	}
+	if err := runMdadm(append(args, devicePaths...)...); err != nil {
+		return nil, err
+	}
+	_ = exec.Command("udevadm", "settle").Run()
+	if err := SaveMDAdmConfig(); err != nil {
+		MDLogger.Error(err)
+	}
+	return GetMDArray(option.Name)
+}
+
+// checkMDArrayUnused make sure array is not mounted, pool member or used by storage
+func checkMDArrayUnused(array *MDArray) error {
+	if mountPoint := utils.Lsblk()[array.Name]["mountpoint"]; len(mountPoint) > 0 {
+		return fmt.Errorf("%w: %s is mounted on %s", DiskInUseError, array.Name, mountPoint)
+	}
+	poolName, err := poolMemberOf(map[string]bool{array.Name: true})
+	if err != nil {
+		return err
+	}
+	if len(poolName) > 0 {
+		return fmt.Errorf("%w: %s is member of pool %s", DiskInUseError, array.Name, poolName)
+	}
+	if storage := partStorageOf(array.Name); len(storage) > 0 {
+		return fmt.Errorf("%w: %s is used by storage %s", DiskInUseError, array.Name, storage)
+	}
+	return nil
+}
+
+// DeleteMDArray stop array and clear superblock of its members
+func DeleteMDArray(identifier string) error {
+	array, err := GetMDArray(identifier)
+	if err != nil {
+		return err
+	}
+	if err = checkMDArrayUnused(array); err != nil {
+		return err
+	}
+	if err = runMdadm("--stop", array.Device); err != nil {
+		return err
+	}
+	for _, member := range array.Members {
+		if err = runMdadm("--zero-superblock", member.Device); err != nil {
+			MDLogger.WithField("device", member.Device).Error(err)
+		}
+	}
+	return SaveMDAdmConfig()
+}
+
+// AddMDMember add unused disk to array, it rebuilds into degraded array or becomes spare
+func AddMDMember(identifier string, device string) error {
+	array, err := GetMDArray(identifier)
+	if err != nil {
+		return err
+	}
+	disk, err := CheckDiskUnused(device)
+	if err != nil {
+		return err
+	}
+	if array.Level == "0" {
+		return fmt.Errorf("%w: raid0 can not have spares", InvalidMDArrayOptionError)
+	}
+	if err = runMdadm("--manage", array.Device, "--add", diskDevicePath(disk.Name)); err != nil {
+		return err
+	}
+	return SaveMDAdmConfig()
+}
+
+// mdFaultTolerance is number of active devices array can lose
+func mdFaultTolerance(array *MDArray) int {
+	switch array.Level {
+	case "1":
+		return array.RaidDisks - 1
+	case "5", "10":
+		return 1
+	case "6":
+		return 2
+	}
+	return 0
+}
+
+// RemoveMDMember fail and remove member from array, removing active member of array without redundancy is refused
+func RemoveMDMember(identifier string, device string) error {
+	array, err := GetMDArray(identifier)
+	if err != nil {
+		return err
+	}
+	name := ResolveDeviceName(device)
+	var target *MDMember
+	for idx := range array.Members {
+		if filepath.Base(array.Members[idx].Device) == name {
+			target = &array.Members[idx]
+		}
+	}
+	if target == nil {
+		return MDMemberNotFoundError
+	}
+	active := target.Slot >= 0 && !strings.Contains(target.State, "faulty")
+	if active && array.Degraded+1 > mdFaultTolerance(array) {
+		return MDArrayRedundancyError
+	}
+	if !strings.Contains(target.State, "faulty") {
+		if err = runMdadm("--manage", array.Device, "--fail", target.Device); err != nil {
+			return err
+		}
+	}
+	if err = runMdadm("--manage", array.Device, "--remove", target.Device); err != nil {
+		return err
+	}
+	if err = runMdadm("--zero-superblock", target.Device); err != nil {
+		MDLogger.WithField("device", target.Device).Error(err)
+	}
+	return SaveMDAdmConfig()
+}
+
+type FormatMDArrayOption struct {
+	Format string `json:"format"`
+	Label  string `json:"label"`
+}
+
+// NewFormatMDArrayTask create filesystem on array, formatted array can be used as part storage source
+func (p *TaskPool) NewFormatMDArrayTask(identifier string, option FormatMDArrayOption, callback PartitionCallback) (Task, error) {
+	if err := validateFormat(option.Format, option.Label); err != nil {
+		return nil, err
+	}
+	array, err := GetMDArray(identifier)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkMDArrayUnused(array); err != nil {
+		return nil, err
+	}
+	extra := PartitionExtra{
+		Action:    PartitionActionFormat,
+		Device:    array.Device,
+		Partition: array.Name,
+	}
+	return p.newPartitionTask(extra, callback, func(task *PartitionTask) error {
+		if err := task.run("wipefs", "-a", task.Extra.Device); err != nil {
+			return err
+		}
+		return task.format(task.Extra.Partition, option.Format, option.Label)
+	}), nil
+}
+
+// mdEventLevels is level of mdadm monitor events, events not listed are info
+var mdEventLevels = map[string]string{
+	"Fail":              EventLevelError,
+	"DeviceDisappeared": EventLevelError,
+	"DegradedArray":     EventLevelError,
+	"FailSpare":         EventLevelWarning,
+	"SparesMissing":     EventLevelWarning,
+}
+
+// MDMonitor turn mdadm monitor events into events of event watcher
+type MDMonitor struct {
+	// Delay is polling interval of mdadm monitor in seconds
+	Delay int
+}
+
+var DefaultMDMonitor = MDMonitor{
+	Delay: 60,
+}
+
+// handleEvent parse line printed by monitor program, which is event, array device and optional component device
+func (m *MDMonitor) handleEvent(line string) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return
+	}
+	event := &database.ZFSEvent{
+		Class:   MDEventClassPrefix + fields[0],
+		Pool:    fields[1],
+		Level:   EventLevelInfo,
+		Message: fmt.Sprintf("%s: %s", fields[1], fields[0]),
+		Time:    time.Now(),
+	}
+	if level, ok := mdEventLevels[fields[0]]; ok {
+		event.Level = level
+	}
+	if len(fields) > 2 {
+		event.Vdev = fields[2]
+		event.Message = fmt.Sprintf("%s: %s %s", fields[1], fields[0], fields[2])
+	}
+	MDLogger.WithField("array", event.Pool).Info(event.Message)
+	DefaultZFSEventWatcher.emit(event)
+}
+
+// follow run mdadm monitor with echo as alert program, so events are printed to its stdout
+func (m *MDMonitor) follow() error {
+	echo, err := exec.LookPath("echo")
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("mdadm", "--monitor", "--scan", "--program", echo, "--delay", strconv.Itoa(m.Delay))
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		m.handleEvent(scanner.Text())
+	}
+	return cmd.Wait()
+}
+
+func (m *MDMonitor) Run() {
+	if _, err := exec.LookPath("mdadm"); err != nil {
+		MDLogger.Warn("mdadm not found, raid monitor disabled")
+		return
+	}
+	go func() {
+		for {
+			if err := m.follow(); err != nil {
+				MDLogger.Error(err)
+			}
+			// monitor exits when there is no array to watch, restart it to pick up new arrays
+			<-time.After(time.Minute)
+		}
+	}()
+}
@@ -21,6 +21,8 @@ type MemoryInfo struct {
 	Free  uint64 `json:"free"`
 	Used  uint64 `json:"used"`
 	Cache uint64 `json:"cache"`
+	// Arc is zfs arc size, it is counted by kernel as used and excluded from Used
+	Arc uint64 `json:"arc"`
 }
 type CpuInfo struct {
 	Idle   uint64 `json:"idle"`
@@ -69,6 +71,14 @@ func (m *SystemMonitor) Run() {
 				Used:  mem.Used,
 				Cache: mem.Cached,
 			}
+			if arc, err := ReadARCStats(); err == nil {
+				m.Monitor.Memory.Arc = arc.Size
+				if m.Monitor.Memory.Used > arc.Size {
+					m.Monitor.Memory.Used -= arc.Size
+				} else {
+					m.Monitor.Memory.Used = 0
+				}
+			}
 			<-time.After(1 * time.Second)
 		}
 
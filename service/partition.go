@@ -0,0 +1,504 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/projectxpolaris/youplus/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	PartitionActionTable  = "table"
+	PartitionActionCreate = "create"
+	PartitionActionFormat = "format"
+	PartitionActionResize = "resize"
+)
+
+var (
+	PartitionNotFoundError      = errors.New("target partition not found")
+	NoPartitionTableError       = errors.New("disk has no partition table")
+	UnsupportedTableLabelError  = errors.New("unsupported partition table type")
+	UnsupportedResizeError      = errors.New("filesystem can not be resized")
+	InvalidPartitionOptionError = errors.New("invalid partition option")
+)
+
+// PartitionTableLabelMapping is sfdisk label of supported table types
+var PartitionTableLabelMapping = map[string]string{
+	"gpt": "gpt",
+	"mbr": "dos",
+}
+
+// DefaultPartitionType is linux filesystem type of each sfdisk label
+var DefaultPartitionType = map[string]string{
+	"gpt": "0FC63DAF-8483-4772-8E79-3D69D8477DE4",
+	"dos": "83",
+}
+
+type PartitionFormat struct {
+	Command   string
+	Args      []string
+	LabelFlag string
+}
+
+// PartitionFormatMapping is filesystems a partition can be formatted with
+var PartitionFormatMapping = map[string]PartitionFormat{
+	"ext4":  {Command: "mkfs.ext4", Args: []string{"-F"}, LabelFlag: "-L"},
+	"xfs":   {Command: "mkfs.xfs", Args: []string{"-f"}, LabelFlag: "-L"},
+	"btrfs": {Command: "mkfs.btrfs", Args: []string{"-f"}, LabelFlag: "-L"},
+	"exfat": {Command: "mkfs.exfat", LabelFlag: "-L"},
+	"vfat":  {Command: "mkfs.vfat", LabelFlag: "-n"},
+}
+
+// poolMemberOf return name of pool which contains any of block device names
+func poolMemberOf(names map[string]bool) (string, error) {
+	pools, err := libzfs.PoolOpenAll()
+	if err != nil {
+		return "", err
+	}
+	defer libzfs.PoolCloseAll(pools)
+	for _, pool := range pools {
+		vt, err := pool.VDevTree()
+		if err != nil {
+			return "", err
+		}
+		if vdevTreeContains(vt, names) {
+			poolName, _ := pool.Name()
+			return poolName, nil
+		}
+	}
+	return "", nil
+}
+
+// partStorageOf return name of part or luks storage which use block device as source
+func partStorageOf(name string) string {
+	sources := map[string]string{}
+	var partStorages []database.PartStorage
+	if err := database.Instance.Find(&partStorages).Error; err != nil {
+		return ""
+	}
+	for _, storage := range partStorages {
+		sources[storage.Source] = storage.Name
+	}
+	var luksStorages []database.LUKSStorage
+	if err := database.Instance.Find(&luksStorages).Error; err != nil {
+		return ""
+	}
+	for _, storage := range luksStorages {
+		sources[storage.Source] = storage.Name
+	}
+	for source, storageName := range sources {
+		if realPath, err := filepath.EvalSymlinks(source); err == nil {
+			source = realPath
+		}
+		if filepath.Base(source) == name {
+			return storageName
+		}
+	}
+	return ""
+}
+
+// CheckPartitionAvailable make sure partition exist and is not mounted, pool member or used by storage
+func CheckPartitionAvailable(name string) (map[string]string, error) {
+	name = filepath.Base(name)
+	block, ok := utils.Lsblk()[name]
+	if !ok || block["type"] != "part" {
+		return nil, PartitionNotFoundError
+	}
+	if len(block["mountpoint"]) > 0 {
+		return nil, fmt.Errorf("%w: %s is mounted on %s", DiskInUseError, name, block["mountpoint"])
+	}
+	poolName, err := poolMemberOf(map[string]bool{name: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(poolName) > 0 {
+		return nil, fmt.Errorf("%w: %s is member of pool %s", DiskInUseError, name, poolName)
+	}
+	if array := mdMemberOf(map[string]bool{name: true}); len(array) > 0 {
+		return nil, fmt.Errorf("%w: %s is member of raid array %s", DiskInUseError, name, array)
+	}
+	if storage := partStorageOf(name); len(storage) > 0 {
+		return nil, fmt.Errorf("%w: %s is used by storage %s", DiskInUseError, name, storage)
+	}
+	return block, nil
+}
+
+// CheckDiskUnused make sure whole disk can be overwritten, it is not mounted, pool member or used by storage
+func CheckDiskUnused(device string) (*Disk, error) {
+	if err := CheckDiskAvailable(device); err != nil {
+		return nil, err
+	}
+	disk := GetDiskByName(filepath.Base(device))
+	if disk == nil {
+		return nil, DiskNotFoundError
+	}
+	// filesystem or swap directly on disk without partition table
+	if mountPoint := utils.Lsblk()[disk.Name]["mountpoint"]; len(mountPoint) > 0 {
+		return nil, fmt.Errorf("%w: %s is mounted on %s", DiskInUseError, disk.Name, mountPoint)
+	}
+	for _, name := range append([]string{disk.Name}, partNames(disk)...) {
+		if storage := partStorageOf(name); len(storage) > 0 {
+			return nil, fmt.Errorf("%w: %s is used by storage %s", DiskInUseError, name, storage)
+		}
+		if holders := blockHolders(name); len(holders) > 0 {
+			return nil, fmt.Errorf("%w: %s is held by %s", DiskInUseError, name, strings.Join(holders, ","))
+		}
+	}
+	return disk, nil
+}
+
+// blockHolders return devices built on top of block device, e.g. dm-crypt, lvm or md
+func blockHolders(name string) []string {
+	holders := make([]string, 0)
+	entries, err := os.ReadDir(filepath.Join("/sys/class/block", name, "holders"))
+	if err != nil {
+		return holders
+	}
+	for _, entry := range entries {
+		holders = append(holders, entry.Name())
+	}
+	return holders
+}
+
+func partNames(disk *Disk) []string {
+	names := make([]string, 0, len(disk.Parts))
+	for _, part := range disk.Parts {
+		names = append(names, part.Name)
+	}
+	return names
+}
+
+// partitionNumber read partition index of partition from sysfs
+func partitionNumber(name string) (int, error) {
+	raw, err := os.ReadFile(filepath.Join("/sys/class/block", name, "partition"))
+	if err != nil {
+		return 0, PartitionNotFoundError
+	}
+	return strconv.Atoi(strings.TrimSpace(string(raw)))
+}
+
+type PartitionExtra struct {
+	Action    string `json:"action"`
+	Device    string `json:"device"`
+	Partition string `json:"partition"`
+	Output    string `json:"output"`
+}
+
+type PartitionCallback struct {
+	OnDone  func(task *PartitionTask)
+	OnError func(task *PartitionTask)
+}
+
+// PartitionTask change partition table or filesystem of a disk
+type PartitionTask struct {
+	BaseTask
+	Extra    PartitionExtra
+	Callback PartitionCallback
+}
+
+func (t *PartitionTask) OnError(err error) {
+	t.SetError(err)
+	if t.Callback.OnError != nil {
+		t.Callback.OnError(t)
+	}
+	logrus.WithFields(logrus.Fields{
+		"action": t.Extra.Action,
+		"device": t.Extra.Device,
+	}).Error(err)
+}
+
+func (t *PartitionTask) run(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	t.Extra.Output += string(out)
+	if err != nil {
+		return fmt.Errorf("%s failed: %s", name, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// fsck check ext filesystem before resize, exit code 1 means errors were corrected
+func (t *PartitionTask) fsck(partitionPath string) error {
+	out, err := exec.Command("e2fsck", "-f", "-y", partitionPath).CombinedOutput()
+	t.Extra.Output += string(out)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("e2fsck failed: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (t *PartitionTask) sfdisk(script string, args ...string) error {
+	out, err := utils.RunSfdiskScript(script, args...)
+	t.Extra.Output += out
+	return err
+}
+
+// settle wait for kernel and udev to pick up partition table change
+func (t *PartitionTask) settle() {
+	_ = exec.Command("partx", "-u", t.Extra.Device).Run()
+	_ = exec.Command("udevadm", "settle").Run()
+}
+
+func (t *PartitionTask) format(partition string, format string, label string) error {
+	definition := PartitionFormatMapping[format]
+	args := append([]string{}, definition.Args...)
+	if len(label) > 0 {
+		args = append(args, definition.LabelFlag, label)
+	}
+	args = append(args, diskDevicePath(partition))
+	return t.run(definition.Command, args...)
+}
+
+func (p *TaskPool) newPartitionTask(extra PartitionExtra, callback PartitionCallback, run func(task *PartitionTask) error) Task {
+	task := PartitionTask{
+		BaseTask: NewBaseTask(),
+		Extra:    extra,
+		Callback: callback,
+	}
+	go func() {
+		if err := run(&task); err != nil {
+			task.OnError(err)
+			return
+		}
+		task.SetStatus(TaskStatusDone)
+		if task.Callback.OnDone != nil {
+			task.Callback.OnDone(&task)
+		}
+	}()
+	p.Lock()
+	p.Tasks = append(p.Tasks, &task)
+	p.Unlock()
+	return &task
+}
+
+func validateFormat(format string, label string) error {
+	if _, ok := PartitionFormatMapping[format]; !ok {
+		return fmt.Errorf("%w: %s", UnsupportedFormatError, format)
+	}
+	if strings.ContainsAny(label, "\"',\n") {
+		return fmt.Errorf("%w: label contains invalid character", InvalidPartitionOptionError)
+	}
+	return nil
+}
+
+type PartitionTableOption struct {
+	Device string `json:"device"`
+	// Label is gpt or mbr
+	Label string `json:"label"`
+}
+
+// NewPartitionTableTask create new empty partition table, all partitions on disk are lost
+func (p *TaskPool) NewPartitionTableTask(option PartitionTableOption, callback PartitionCallback) (Task, error) {
+	label, ok := PartitionTableLabelMapping[option.Label]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", UnsupportedTableLabelError, option.Label)
+	}
+	disk, err := CheckDiskUnused(option.Device)
+	if err != nil {
+		return nil, err
+	}
+	extra := PartitionExtra{Action: PartitionActionTable, Device: diskDevicePath(disk.Name)}
+	return p.newPartitionTask(extra, callback, func(task *PartitionTask) error {
+		if err := task.sfdisk(fmt.Sprintf("label: %s\n", label), "--wipe", "always", task.Extra.Device); err != nil {
+			return err
+		}
+		task.settle()
+		return nil
+	}), nil
+}
+
+type CreatePartitionOption struct {
+	Device string `json:"device"`
+	// Start and Size are in bytes, zero start use first free space and zero size use all free space after start
+	Start uint64 `json:"start"`
+	Size  uint64 `json:"size"`
+	// Type is type GUID on gpt or hex type code on mbr, default is linux filesystem
+	Type string `json:"type"`
+	// Label is gpt partition name
+	Label string `json:"label"`
+	// Format and FSLabel format new partition when set
+	Format  string `json:"format"`
+	FSLabel string `json:"fsLabel"`
+}
+
+// NewCreatePartitionTask append partition to partition table of disk and optionally format it
+func (p *TaskPool) NewCreatePartitionTask(option CreatePartitionOption, callback PartitionCallback) (Task, error) {
+	disk := GetDiskByName(filepath.Base(option.Device))
+	if disk == nil {
+		return nil, DiskNotFoundError
+	}
+	names := map[string]bool{disk.Name: true}
+	for _, part := range disk.Parts {
+		names[part.Name] = true
+	}
+	poolName, err := poolMemberOf(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(poolName) > 0 {
+		return nil, fmt.Errorf("%w: %s is member of pool %s", DiskInUseError, disk.Name, poolName)
+	}
+	devicePath := diskDevicePath(disk.Name)
+	table, err := utils.ReadPartitionTable(devicePath)
+	if err != nil {
+		return nil, NoPartitionTableError
+	}
+	if len(option.Format) > 0 {
+		if err = validateFormat(option.Format, option.FSLabel); err != nil {
+			return nil, err
+		}
+	}
+	if strings.ContainsAny(option.Label+option.Type, "\"',\n") {
+		return nil, fmt.Errorf("%w: label or type contains invalid character", InvalidPartitionOptionError)
+	}
+	if len(option.Label) > 0 && table.Label != "gpt" {
+		return nil, fmt.Errorf("%w: partition label requires gpt table", InvalidPartitionOptionError)
+	}
+	if option.Start%table.SectorSize != 0 || option.Size%table.SectorSize != 0 {
+		return nil, fmt.Errorf("%w: start and size must be multiple of sector size %d", InvalidPartitionOptionError, table.SectorSize)
+	}
+	fields := make([]string, 0)
+	if option.Start > 0 {
+		fields = append(fields, fmt.Sprintf("start=%d", option.Start/table.SectorSize))
+	}
+	if option.Size > 0 {
+		fields = append(fields, fmt.Sprintf("size=%d", option.Size/table.SectorSize))
+	}
+	partType := option.Type
+	if len(partType) == 0 {
+		partType = DefaultPartitionType[table.Label]
+	}
+	fields = append(fields, fmt.Sprintf("type=%s", partType))
+	if len(option.Label) > 0 {
+		fields = append(fields, fmt.Sprintf("name=\"%s\"", option.Label))
+	}
+	existed := map[string]bool{}
+	for _, part := range table.Partitions {
+		existed[part.Node] = true
+	}
+	extra := PartitionExtra{Action: PartitionActionCreate, Device: devicePath}
+	return p.newPartitionTask(extra, callback, func(task *PartitionTask) error {
+		if err := task.sfdisk(strings.Join(fields, ", ")+"\n", "--append", devicePath); err != nil {
+			return err
+		}
+		task.settle()
+		current, err := utils.ReadPartitionTable(devicePath)
+		if err != nil {
+			return err
+		}
+		for _, part := range current.Partitions {
+			if !existed[part.Node] {
+				task.Extra.Partition = filepath.Base(part.Node)
+			}
+		}
+		if len(option.Format) == 0 {
+			return nil
+		}
+		if len(task.Extra.Partition) == 0 {
+			return PartitionNotFoundError
+		}
+		return task.format(task.Extra.Partition, option.Format, option.FSLabel)
+	}), nil
+}
+
+type FormatPartitionOption struct {
+	Partition string `json:"partition"`
+	Format    string `json:"format"`
+	Label     string `json:"label"`
+}
+
+// NewFormatPartitionTask create filesystem on partition, existing data is lost
+func (p *TaskPool) NewFormatPartitionTask(option FormatPartitionOption, callback PartitionCallback) (Task, error) {
+	if err := validateFormat(option.Format, option.Label); err != nil {
+		return nil, err
+	}
+	block, err := CheckPartitionAvailable(option.Partition)
+	if err != nil {
+		return nil, err
+	}
+	extra := PartitionExtra{
+		Action:    PartitionActionFormat,
+		Device:    diskDevicePath(block["pkname"]),
+		Partition: block["name"],
+	}
+	return p.newPartitionTask(extra, callback, func(task *PartitionTask) error {
+		if err := task.run("wipefs", "-a", diskDevicePath(task.Extra.Partition)); err != nil {
+			return err
+		}
+		return task.format(task.Extra.Partition, option.Format, option.Label)
+	}), nil
+}
+
+type ResizePartitionOption struct {
+	Partition string `json:"partition"`
+	// Size is new size in bytes, zero grow partition to all free space after it
+	Size uint64 `json:"size"`
+}
+
+// NewResizePartitionTask change partition size, filesystem on it is resized together when supported
+func (p *TaskPool) NewResizePartitionTask(option ResizePartitionOption, callback PartitionCallback) (Task, error) {
+	block, err := CheckPartitionAvailable(option.Partition)
+	if err != nil {
+		return nil, err
+	}
+	fsType := block["fstype"]
+	if len(fsType) > 0 && fsType != "ext4" {
+		// xfs and btrfs only resize mounted, fat and exfat can not be resized here
+		return nil, fmt.Errorf("%w: %s", UnsupportedResizeError, fsType)
+	}
+	number, err := partitionNumber(block["name"])
+	if err != nil {
+		return nil, err
+	}
+	devicePath := diskDevicePath(block["pkname"])
+	table, err := utils.ReadPartitionTable(devicePath)
+	if err != nil {
+		return nil, NoPartitionTableError
+	}
+	if option.Size%table.SectorSize != 0 {
+		return nil, fmt.Errorf("%w: size must be multiple of sector size %d", InvalidPartitionOptionError, table.SectorSize)
+	}
+	currentSize, _ := strconv.ParseUint(block["size"], 10, 64)
+	shrink := option.Size > 0 && option.Size < currentSize
+	script := ", +\n"
+	if option.Size > 0 {
+		script = fmt.Sprintf(", %d\n", option.Size/table.SectorSize)
+	}
+	extra := PartitionExtra{
+		Action:    PartitionActionResize,
+		Device:    devicePath,
+		Partition: block["name"],
+	}
+	return p.newPartitionTask(extra, callback, func(task *PartitionTask) error {
+		partitionPath := diskDevicePath(task.Extra.Partition)
+		if len(fsType) > 0 {
+			if err := task.fsck(partitionPath); err != nil {
+				return err
+			}
+		}
+		if shrink && len(fsType) > 0 {
+			if err := task.run("resize2fs", partitionPath, fmt.Sprintf("%dK", option.Size/1024)); err != nil {
+				return err
+			}
+		}
+		if err := task.sfdisk(script, "-N", strconv.Itoa(number), devicePath); err != nil {
+			return err
+		}
+		task.settle()
+		if !shrink && len(fsType) > 0 {
+			return task.run("resize2fs", partitionPath)
+		}
+		return nil
+	}), nil
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	JobTypeTrim = "trim"
+)
+
+var (
+	UnsupportedJobTypeError = errors.New("unsupported job type")
+	InvalidJobIntervalError = errors.New("job interval must be at least one minute")
+	JobNotFoundError        = errors.New("scheduled job not found")
+)
+
+var ScheduleLogger = logrus.New().WithFields(logrus.Fields{
+	"scope": "Schedule",
+})
+
+// ScheduledJobRunner run one job on target, returned error is recorded as last error of job
+type ScheduledJobRunner func(target string) error
+
+var ScheduledJobRunners = map[string]ScheduledJobRunner{
+	JobTypeTrim:       runTrimJob,
+	JobTypeSmartShort: runSmartShortJob,
+	JobTypeSmartLong:  runSmartLongJob,
+}
+
+type ScheduledJobOption struct {
+	Type     string `json:"type"`
+	Target   string `json:"target"`
+	Interval int64  `json:"interval"`
+	Enable   bool   `json:"enable"`
+	// StartAt is unix time of first run, zero means one interval from now
+	StartAt int64 `json:"startAt"`
+}
+
+func validateScheduledJob(jobType string, interval int64) error {
+	if _, ok := ScheduledJobRunners[jobType]; !ok {
+		return UnsupportedJobTypeError
+	}
+	if interval < 60 {
+		return InvalidJobIntervalError
+	}
+	return nil
+}
+
+func CreateScheduledJob(option ScheduledJobOption) (*database.ScheduledJob, error) {
+	if err := validateScheduledJob(option.Type, option.Interval); err != nil {
+		return nil, err
+	}
+	job := &database.ScheduledJob{
+		Type:     option.Type,
+		Target:   option.Target,
+		Interval: option.Interval,
+		Enable:   option.Enable,
+		NextRun:  time.Now().Add(time.Duration(option.Interval) * time.Second),
+	}
+	if option.StartAt > 0 {
+		job.NextRun = time.Unix(option.StartAt, 0)
+	}
+	if err := database.Instance.Create(job).Error; err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func GetScheduledJobs(jobType string) ([]database.ScheduledJob, error) {
+	jobs := make([]database.ScheduledJob, 0)
+	query := database.Instance.Model(&database.ScheduledJob{})
+	if len(jobType) > 0 {
+		query = query.Where("type = ?", jobType)
+	}
+	err := query.Order("id").Find(&jobs).Error
+	return jobs, err
+}
+
+func getScheduledJob(id uint) (*database.ScheduledJob, error) {
+	var job database.ScheduledJob
+	if err := database.Instance.First(&job, id).Error; err != nil {
+		return nil, JobNotFoundError
+	}
+	return &job, nil
+}
+
+// UpdateScheduledJob change target, interval and enable of job, type can not be changed
+func UpdateScheduledJob(id uint, option ScheduledJobOption) (*database.ScheduledJob, error) {
+	job, err := getScheduledJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateScheduledJob(job.Type, option.Interval); err != nil {
+		return nil, err
+	}
+	if job.Interval != option.Interval || option.StartAt > 0 {
+		job.NextRun = time.Now().Add(time.Duration(option.Interval) * time.Second)
+		if option.StartAt > 0 {
+			job.NextRun = time.Unix(option.StartAt, 0)
+		}
+	}
+	job.Target = option.Target
+	job.Interval = option.Interval
+	job.Enable = option.Enable
+	if err = database.Instance.Save(job).Error; err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func DeleteScheduledJob(id uint) error {
+	job, err := getScheduledJob(id)
+	if err != nil {
+		return err
+	}
+	return database.Instance.Unscoped().Delete(job).Error
+}
+
+// Scheduler check due jobs every minute, a job is not started again while previous run is not finished
+type Scheduler struct {
+	running map[uint]bool
+	sync.Mutex
+}
+
+var DefaultScheduler = Scheduler{
+	running: map[uint]bool{},
+}
+
+func (s *Scheduler) Run() {
+	go func() {
+		for {
+			s.runDueJobs()
+			<-time.After(time.Minute)
+		}
+	}()
+}
+
+func (s *Scheduler) runDueJobs() {
+	var jobs []database.ScheduledJob
+	err := database.Instance.Where("enable = ? AND next_run <= ?", true, time.Now()).Find(&jobs).Error
+	if err != nil {
+		ScheduleLogger.Error(err)
+		return
+	}
+	for idx := range jobs {
+		job := jobs[idx]
+		s.Lock()
+		if s.running[job.ID] {
+			s.Unlock()
+			continue
+		}
+		s.running[job.ID] = true
+		s.Unlock()
+		go s.runJob(&job)
+	}
+}
+
+// RunJobNow run job immediately without changing next run time
+func (s *Scheduler) RunJobNow(id uint) error {
+	job, err := getScheduledJob(id)
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	if s.running[job.ID] {
+		s.Unlock()
+		return nil
+	}
+	s.running[job.ID] = true
+	s.Unlock()
+	go s.runJob(job)
+	return nil
+}
+
+func (s *Scheduler) runJob(job *database.ScheduledJob) {
+	defer func() {
+		s.Lock()
+		delete(s.running, job.ID)
+		s.Unlock()
+	}()
+	logger := ScheduleLogger.WithFields(logrus.Fields{
+		"id":     job.ID,
+		"type":   job.Type,
+		"target": job.Target,
+	})
+	now := time.Now()
+	updates := map[string]interface{}{
+		"last_run":   now,
+		"last_error": "",
+	}
+	if !job.NextRun.After(now) {
+		// skip missed runs so a long downtime does not start a burst of jobs
+		interval := time.Duration(job.Interval) * time.Second
+		next := job.NextRun
+		for !next.After(now) {
+			next = next.Add(interval)
+		}
+		updates["next_run"] = next
+	}
+	runner, ok := ScheduledJobRunners[job.Type]
+	if !ok {
+		updates["last_error"] = UnsupportedJobTypeError.Error()
+	} else if err := runner(job.Target); err != nil {
+		logger.Error(err)
+		updates["last_error"] = err.Error()
+	} else {
+		logger.Info("scheduled job run")
+	}
+	if err := database.Instance.Model(&database.ScheduledJob{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+		logger.Error(err)
+	}
+}
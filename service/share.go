@@ -19,6 +19,7 @@ import (
 var (
 	PartNotFoundError = errors.New("target part name not found")
 	PartNotMountError = errors.New("target part not mounted")
+	ShareExistsError  = errors.New("share folder with same name already exists")
 )
 
 type NewShareFolderOption struct {
@@ -52,7 +53,7 @@ func CreateNewShareFolder(option *NewShareFolderOption) error {
 	switch storage.(type) {
 	case *ZFSPoolStorage:
 		shareFolder.ZFSStorageId = storage.GetId()
-	case *DiskPartStorage:
+	case *DiskPartStorage, *LUKSPartStorage:
 		shareFolder.PartStorageId = storage.GetId()
 	case *PathStorage:
 		shareFolder.PathStorageId = storage.GetId()
@@ -91,13 +92,15 @@ func getSMBUserAndUserGroupList(users []*database.User, groups []*database.UserG
 	return list
 }
 func SyncShareFolderOptionToSMB(folder *database.ShareFolder) error {
+	// share folder on locked dataset is unavailable until unlocked
+	available := folder.Enable && !IsPathLocked(folder.Path)
 	properties := map[string]string{
 		"path":           folder.Path,
 		"create mask":    "0775",
 		"directory mask": "0775",
 		"read only":      utils.GetSmbBoolText(folder.Readonly),
-		"available":      utils.GetSmbBoolText(folder.Enable),
-		"browseable":     utils.GetSmbBoolText(folder.Enable),
+		"available":      utils.GetSmbBoolText(available),
+		"browseable":     utils.GetSmbBoolText(available),
 		"public":         utils.GetSmbBoolText(folder.Public),
 		"force user":     "root",
 		"force group":    "root",
@@ -151,6 +154,31 @@ func SyncShareFolderOptionToSMB(folder *database.ShareFolder) error {
 	}
 	return nil
 }
+
+// CheckShareNameAvailable make sure no share folder or smb section use the name, smb sync would overwrite it
+func CheckShareNameAvailable(name string) error {
+	var count int64
+	err := database.Instance.Model(&database.ShareFolder{}).Where("name = ?", name).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %s", ShareExistsError, name)
+	}
+	return yousmb.ExecWithRPCClient(func(client rpc.YouSMBServiceClient) error {
+		response, err := client.GetConfig(yousmb.GetRPCTimeoutContext(), &rpc.Empty{})
+		if err != nil {
+			return err
+		}
+		for _, section := range response.Sections {
+			if section.Name != nil && strings.EqualFold(*section.Name, name) {
+				return fmt.Errorf("%w: %s", ShareExistsError, name)
+			}
+		}
+		return nil
+	})
+}
+
 func GetShareFolders() ([]*database.ShareFolder, error) {
 	var folders []*database.ShareFolder
 	err := database.Instance.
@@ -271,7 +299,7 @@ func UpdateSMBConfig(option *UpdateShareFolderOption) error {
 		switch storage.(type) {
 		case *ZFSPoolStorage:
 			folder.ZFSStorageId = storage.GetId()
-		case *DiskPartStorage:
+		case *DiskPartStorage, *LUKSPartStorage:
 			folder.PartStorageId = storage.GetId()
 		case *PathStorage:
 			folder.PathStorageId = storage.GetId()
@@ -371,6 +399,9 @@ func UpdateSMBConfig(option *UpdateShareFolderOption) error {
 		folder.Public = *option.Public
 	}
 	if option.Enable != nil {
+		if *option.Enable && IsPathLocked(folder.Path) {
+			return DatasetLockedError
+		}
 		folder.Enable = *option.Enable
 	}
 	if option.Readonly != nil {
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	JobTypeSmartShort = "smart-short"
+	JobTypeSmartLong  = "smart-long"
+)
+
+// SmartAlertClass is class of event created when smart attribute of disk worsens or crosses threshold
+const SmartAlertClass = "youplus.disk.smart"
+
+var UnsupportedSelfTestError = errors.New("unsupported self-test type")
+
+var SmartLogger = logrus.New().WithFields(logrus.Fields{
+	"scope": "Smart",
+})
+
+// SmartTemperatureLimit and NVMeTemperatureLimit are temperature in celsius above which alert fires
+var (
+	SmartTemperatureLimit int64 = 60
+	NVMeTemperatureLimit  int64 = 70
+)
+
+// SmartPercentUsedLimit is nvme endurance used percentage at which alert fires
+var SmartPercentUsedLimit int64 = 90
+
+// ataAttributeFields is sampled ata attribute id to sample field
+var ataAttributeFields = map[int64]string{
+	5:   "reallocated",
+	197: "pending",
+	198: "uncorrectable",
+	199: "crcErrors",
+}
+
+func jsonInt(value interface{}) int64 {
+	switch v := value.(type) {
+	case float64:
+		return int64(v)
+	case string:
+		parsed, _ := strconv.ParseInt(strings.ReplaceAll(v, ",", ""), 10, 64)
+		return parsed
+	case map[string]interface{}:
+		return jsonInt(v["value"])
+	}
+	return 0
+}
+
+func jsonMap(value interface{}) map[string]interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+func jsonList(value interface{}) []interface{} {
+	if l, ok := value.([]interface{}); ok {
+		return l
+	}
+	return []interface{}{}
+}
+
+// NewSmartSample read key attributes from smartctl json or nvme smart-log output
+func NewSmartSample(d *Disk, info map[string]interface{}) *database.SmartSample {
+	sample := &database.SmartSample{
+		Disk:    d.StablePath,
+		Time:    time.Now(),
+		Healthy: true,
+	}
+	if strings.HasPrefix(d.Name, "nvme") {
+		// nvme smart-log report temperature in kelvin
+		sample.Temperature = jsonInt(info["temperature"]) - 273
+		sample.PowerOnHours = jsonInt(info["power_on_hours"])
+		sample.MediaErrors = jsonInt(info["media_errors"])
+		sample.PercentUsed = jsonInt(info["percent_used"])
+		if _, ok := info["percent_used"]; !ok {
+			sample.PercentUsed = jsonInt(info["percentage_used"])
+		}
+		sample.Healthy = jsonInt(info["critical_warning"]) == 0
+		return sample
+	}
+	if status, ok := info["smart_status"]; ok {
+		sample.Healthy = jsonMap(status)["passed"] == true
+	}
+	sample.Temperature = jsonInt(jsonMap(info["temperature"])["current"])
+	sample.PowerOnHours = jsonInt(jsonMap(info["power_on_time"])["hours"])
+	failing := make([]string, 0)
+	for _, item := range jsonList(jsonMap(info["ata_smart_attributes"])["table"]) {
+		attr := jsonMap(item)
+		id := jsonInt(attr["id"])
+		threshold := jsonInt(attr["thresh"])
+		if threshold > 0 && jsonInt(attr["value"]) <= threshold {
+			failing = append(failing, fmt.Sprintf("%v", attr["name"]))
+		}
+		raw := jsonInt(jsonMap(attr["raw"])["value"])
+		switch ataAttributeFields[id] {
+		case "reallocated":
+			sample.Reallocated = raw
+		case "pending":
+			sample.Pending = raw
+		case "uncorrectable":
+			sample.Uncorrectable = raw
+		case "crcErrors":
+			sample.CRCErrors = raw
+		}
+	}
+	sample.Failing = strings.Join(failing, ",")
+	return sample
+}
+
+// smartAlerts compare sample with previous one of same disk, previous is nil for first sample
+func smartAlerts(sample *database.SmartSample, previous *database.SmartSample, nvme bool) []string {
+	alerts := make([]string, 0)
+	if previous == nil {
+		previous = &database.SmartSample{Healthy: true}
+	}
+	if !sample.Healthy && previous.Healthy {
+		alerts = append(alerts, "smart overall health check failed")
+	}
+	for _, counter := range []struct {
+		Name            string
+		Current, Before int64
+	}{
+		{Name: "reallocated sectors", Current: sample.Reallocated, Before: previous.Reallocated},
+		{Name: "pending sectors", Current: sample.Pending, Before: previous.Pending},
+		{Name: "uncorrectable sectors", Current: sample.Uncorrectable, Before: previous.Uncorrectable},
+		{Name: "crc errors", Current: sample.CRCErrors, Before: previous.CRCErrors},
+		{Name: "media errors", Current: sample.MediaErrors, Before: previous.MediaErrors},
+	} {
+		if counter.Current > counter.Before {
+			alerts = append(alerts, fmt.Sprintf("%s increased from %d to %d", counter.Name, counter.Before, counter.Current))
+		}
+	}
+	limit := SmartTemperatureLimit
+	if nvme {
+		limit = NVMeTemperatureLimit
+	}
+	if sample.Temperature > limit && previous.Temperature <= limit {
+		alerts = append(alerts, fmt.Sprintf("temperature %d°C is above %d°C", sample.Temperature, limit))
+	}
+	if sample.PercentUsed >= SmartPercentUsedLimit && previous.PercentUsed < SmartPercentUsedLimit {
+		alerts = append(alerts, fmt.Sprintf("%d%% of endurance is used", sample.PercentUsed))
+	}
+	if len(sample.Failing) > 0 && sample.Failing != previous.Failing {
+		alerts = append(alerts, fmt.Sprintf("attributes at or below threshold: %s", sample.Failing))
+	}
+	return alerts
+}
+
+func smartctlJSON(args ...string) (map[string]interface{}, error) {
+	args = append([]string{"--json"}, args...)
+	// smartctl exit status is a bit mask of disk problems, output is still valid json
+	output, err := exec.Command("smartctl", args...).Output()
+	result := map[string]interface{}{}
+	if jsonErr := json.Unmarshal(output, &result); jsonErr != nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, jsonErr
+	}
+	return result, nil
+}
+
+// StartSelfTest start short or long self-test of disk, test runs in disk firmware in background
+func StartSelfTest(name string, testType string) error {
+	if testType != "short" && testType != "long" {
+		return fmt.Errorf("%w: %s", UnsupportedSelfTestError, testType)
+	}
+	disk := GetDiskByName(name)
+	if disk == nil {
+		return DiskNotFoundError
+	}
+	result, err := smartctlJSON("-t", testType, diskDevicePath(disk.Name))
+	if err != nil {
+		return err
+	}
+	for _, message := range jsonList(jsonMap(result["smartctl"])["messages"]) {
+		if jsonMap(message)["severity"] == "error" {
+			return fmt.Errorf("self-test failed to start: %v", jsonMap(message)["string"])
+		}
+	}
+	return nil
+}
+
+func runSmartShortJob(target string) error {
+	return StartSelfTest(target, "short")
+}
+
+func runSmartLongJob(target string) error {
+	return StartSelfTest(target, "long")
+}
+
+// ReadSelfTestLog read ata or nvme self-test log of disk, newest first
+func ReadSelfTestLog(name string) ([]database.SmartTestResult, error) {
+	result, err := smartctlJSON("-n", "standby", "-l", "selftest", diskDevicePath(name))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]database.SmartTestResult, 0)
+	for _, item := range jsonList(jsonMap(jsonMap(result["ata_smart_self_test_log"])["standard"])["table"]) {
+		entry := jsonMap(item)
+		status := jsonMap(entry["status"])
+		entries = append(entries, database.SmartTestResult{
+			Disk:          name,
+			Type:          fmt.Sprintf("%v", jsonMap(entry["type"])["string"]),
+			Status:        fmt.Sprintf("%v", status["string"]),
+			Passed:        status["passed"] == true,
+			LifetimeHours: jsonInt(entry["lifetime_hours"]),
+		})
+	}
+	for _, item := range jsonList(jsonMap(result["nvme_self_test_log"])["table"]) {
+		entry := jsonMap(item)
+		status := jsonMap(entry["self_test_result"])
+		entries = append(entries, database.SmartTestResult{
+			Disk:          name,
+			Type:          fmt.Sprintf("%v", jsonMap(entry["self_test_code"])["string"]),
+			Status:        fmt.Sprintf("%v", status["string"]),
+			Passed:        jsonInt(status["value"]) == 0,
+			LifetimeHours: jsonInt(entry["power_on_hours"]),
+		})
+	}
+	return entries, nil
+}
+
+// SmartMonitor sample smart attributes of all disks daily and record self-test results
+type SmartMonitor struct {
+	// SampleInterval between attribute samples of same disk, CheckInterval between checks
+	SampleInterval time.Duration
+	CheckInterval  time.Duration
+}
+
+var DefaultSmartMonitor = SmartMonitor{
+	SampleInterval: 24 * time.Hour,
+	CheckInterval:  time.Hour,
+}
+
+func (m *SmartMonitor) Run() {
+	if _, err := exec.LookPath("smartctl"); err != nil {
+		SmartLogger.Info("smartctl not found, skip smart monitor")
+		return
+	}
+	go func() {
+		for {
+			for _, disk := range DefaultDiskInventory.GetDisks() {
+				m.check(disk)
+			}
+			<-time.After(m.CheckInterval)
+		}
+	}()
+}
+
+func (m *SmartMonitor) alert(disk string, level string, message string) {
+	DefaultZFSEventWatcher.emit(&database.ZFSEvent{
+		Class:   SmartAlertClass,
+		Vdev:    disk,
+		Level:   level,
+		Message: fmt.Sprintf("%s: %s", disk, message),
+		Time:    time.Now(),
+	})
+}
+
+func (m *SmartMonitor) check(disk *Disk) {
+	// polling sleeping disk spins it up, sample again when it is active
+	if IsDiskSleeping(disk.Name) {
+		return
+	}
+	logger := SmartLogger.WithField("disk", disk.Name)
+	var previous database.SmartSample
+	err := database.Instance.Where("disk = ?", disk.StablePath).Order("time desc").First(&previous).Error
+	hasPrevious := err == nil
+	if !hasPrevious || time.Since(previous.Time) >= m.SampleInterval {
+		if err = m.sample(disk, &previous, hasPrevious); err != nil {
+			logger.Error(err)
+		}
+	}
+	if err = m.recordSelfTests(disk); err != nil {
+		logger.Error(err)
+	}
+}
+
+// readSmartSampleInfo read smart data for sample, smartctl exit status of failing disk is not an error
+func readSmartSampleInfo(disk *Disk) (map[string]interface{}, error) {
+	if strings.HasPrefix(disk.Name, "nvme") {
+		return disk.GetSmartInfo()
+	}
+	info, err := smartctlJSON("--all", diskDevicePath(disk.Name))
+	if err != nil {
+		return nil, err
+	}
+	// bit 0 and 1 mean command line error or device could not be opened, output has no smart data
+	if exitStatus := jsonInt(jsonMap(info["smartctl"])["exit_status"]); exitStatus&0x3 != 0 {
+		return nil, fmt.Errorf("smartctl failed with exit status %d", exitStatus)
+	}
+	return info, nil
+}
+
+func (m *SmartMonitor) sample(disk *Disk, previous *database.SmartSample, hasPrevious bool) error {
+	info, err := readSmartSampleInfo(disk)
+	if err != nil {
+		return err
+	}
+	sample := NewSmartSample(disk, info)
+	if err = database.Instance.Create(sample).Error; err != nil {
+		return err
+	}
+	if !hasPrevious {
+		previous = nil
+	}
+	for _, message := range smartAlerts(sample, previous, strings.HasPrefix(disk.Name, "nvme")) {
+		level := EventLevelWarning
+		if !sample.Healthy {
+			level = EventLevelError
+		}
+		m.alert(disk.Name, level, message)
+	}
+	return nil
+}
+
+// recordSelfTests store self-test log entries not seen before, failed tests fire alert,
+// entries found on first import of disk are old results so they are stored without alert
+func (m *SmartMonitor) recordSelfTests(disk *Disk) error {
+	entries, err := ReadSelfTestLog(disk.Name)
+	if err != nil {
+		return err
+	}
+	var imported int64
+	err = database.Instance.Model(&database.SmartTestResult{}).Where("disk = ?", disk.StablePath).Count(&imported).Error
+	if err != nil {
+		return err
+	}
+	for idx := range entries {
+		entry := entries[idx]
+		entry.Disk = disk.StablePath
+		var count int64
+		err = database.Instance.Model(&database.SmartTestResult{}).
+			Where("disk = ? AND type = ? AND lifetime_hours = ?", entry.Disk, entry.Type, entry.LifetimeHours).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if err = database.Instance.Create(&entry).Error; err != nil {
+			return err
+		}
+		if !entry.Passed && imported > 0 {
+			m.alert(disk.Name, EventLevelError, fmt.Sprintf("%s self-test: %s", entry.Type, entry.Status))
+		}
+	}
+	return nil
+}
+
+// smartDiskKey return stable path of disk which smart records are keyed by, detached disk is given by the key itself
+func smartDiskKey(name string) string {
+	if disk := GetDiskByName(name); disk != nil {
+		return disk.StablePath
+	}
+	return name
+}
+
+func GetSmartHistory(name string, since int64) ([]database.SmartSample, error) {
+	samples := make([]database.SmartSample, 0)
+	err := database.Instance.
+		Where("disk = ? AND time >= ?", smartDiskKey(name), time.Unix(since, 0)).
+		Order("time").
+		Find(&samples).Error
+	return samples, err
+}
+
+func GetSelfTestResults(name string) ([]database.SmartTestResult, error) {
+	results := make([]database.SmartTestResult, 0)
+	err := database.Instance.
+		Where("disk = ?", smartDiskKey(name)).
+		Order("lifetime_hours desc").
+		Find(&results).Error
+	return results, err
+}
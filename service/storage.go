@@ -3,19 +3,50 @@ package service
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
 	"github.com/projectxpolaris/youplus/database"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 )
 
 var StorageNotFoundError = errors.New("target storage not found")
-var DefaultStoragePool = StoragePool{[]Storage{}}
+var DefaultStoragePool = StoragePool{Storages: []Storage{}}
 var StoragePoolLogger = logrus.New().WithFields(logrus.Fields{
 	"scope": "StorageManager",
 })
 
+const (
+	StorageStatusOnline   = "online"
+	StorageStatusDegraded = "degraded"
+	StorageStatusOffline  = "offline"
+	StorageStatusLocked   = "locked"
+)
+
 type StoragePool struct {
-	Storages []Storage
+	// Storages is guarded by storagesLock, use Add and List instead of accessing it directly
+	Storages     []Storage
+	storagesLock sync.RWMutex
+	// status is storage id to status, storages not in it are online
+	status     map[string]string
+	statusLock sync.RWMutex
+}
+
+// Add append storage to storage pool
+func (p *StoragePool) Add(storage Storage) {
+	p.storagesLock.Lock()
+	defer p.storagesLock.Unlock()
+	p.Add(storage)
+}
+
+// List return copy of storages in storage pool
+func (p *StoragePool) List() []Storage {
+	p.storagesLock.RLock()
+	defer p.storagesLock.RUnlock()
+	return append([]Storage{}, p.Storages...)
 }
 
 func (p *StoragePool) LoadStorage() error {
@@ -27,7 +58,7 @@ func (p *StoragePool) LoadStorage() error {
 	for _, partStorage := range diskPartStorage {
 		diskPartStorage := &DiskPartStorage{}
 		diskPartStorage.LoadFromSave(partStorage)
-		p.Storages = append(p.Storages, diskPartStorage)
+		p.Add(diskPartStorage)
 	}
 	var ZFSStorageList []*database.ZFSStorage
 	err = database.Instance.Find(&ZFSStorageList).Error
@@ -41,7 +72,7 @@ func (p *StoragePool) LoadStorage() error {
 			logrus.Error(err)
 			continue
 		}
-		p.Storages = append(p.Storages, s)
+		p.Add(s)
 	}
 	var FolderStorageList []*database.FolderStorage
 	err = database.Instance.Find(&FolderStorageList).Error
@@ -55,13 +86,23 @@ func (p *StoragePool) LoadStorage() error {
 			logrus.Error(err)
 			continue
 		}
-		p.Storages = append(p.Storages, s)
+		p.Add(s)
+	}
+	var LUKSStorageList []*database.LUKSStorage
+	err = database.Instance.Find(&LUKSStorageList).Error
+	if err != nil {
+		return err
 	}
-	StoragePoolLogger.Info(fmt.Sprintf("success load %d storages", len(p.Storages)))
+	for _, luksStorage := range LUKSStorageList {
+		s := &LUKSPartStorage{}
+		s.LoadFromSave(luksStorage)
+		p.Add(s)
+	}
+	StoragePoolLogger.Info(fmt.Sprintf("success load %d storages", len(p.List())))
 	return nil
 }
 func (p *StoragePool) SaveStorage() error {
-	for _, storage := range p.Storages {
+	for _, storage := range p.List() {
 		storage.SaveData()
 	}
 	return nil
@@ -73,21 +114,21 @@ func (p *StoragePool) NewStorage(source string, storageType string) error {
 		if err != nil {
 			return err
 		}
-		p.Storages = append(p.Storages, storage)
+		p.Add(storage)
 	}
 	if storageType == "ZFSPool" {
 		storage, err := CreateZFSStorage(source)
 		if err != nil {
 			return err
 		}
-		p.Storages = append(p.Storages, storage)
+		p.Add(storage)
 	}
 	if storageType == "Path" {
 		storage, err := CreatePathStorage(source)
 		if err != nil {
 			return err
 		}
-		p.Storages = append(p.Storages, storage)
+		p.Add(storage)
 	}
 	err := p.SaveStorage()
 	if err != nil {
@@ -96,16 +137,17 @@ func (p *StoragePool) NewStorage(source string, storageType string) error {
 	return nil
 }
 
-func (p *StoragePool) RemoveStorage(id string) error {
-	var targetStorage Storage
-	var targetIndex int
-	for idx, storage := range p.Storages {
-		if storage.GetId() == id {
-			targetStorage = storage
-			targetIndex = idx
-			break
-		}
+func (p *StoragePool) NewLUKSStorage(option LUKSStorageOption) error {
+	storage, err := NewLUKSPartStorage(option)
+	if err != nil {
+		return err
 	}
+	p.Add(storage)
+	return p.SaveStorage()
+}
+
+func (p *StoragePool) RemoveStorage(id string) error {
+	targetStorage := p.GetStorageById(id)
 	if targetStorage == nil {
 		return nil
 	}
@@ -115,15 +157,38 @@ func (p *StoragePool) RemoveStorage(id string) error {
 	}
 
 	// update config
-	p.Storages[targetIndex] = p.Storages[len(p.Storages)-1]
-	p.Storages = p.Storages[0 : len(p.Storages)-1]
+	p.storagesLock.Lock()
+	for idx, storage := range p.Storages {
+		if storage == targetStorage {
+			p.Storages[idx] = p.Storages[len(p.Storages)-1]
+			p.Storages = p.Storages[0 : len(p.Storages)-1]
+			break
+		}
+	}
+	p.storagesLock.Unlock()
 	err = p.SaveStorage()
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// UnloadZFSPoolStorages remove storages of pool from storage pool, saved data is kept
+func (p *StoragePool) UnloadZFSPoolStorages(poolName string) {
+	p.storagesLock.Lock()
+	defer p.storagesLock.Unlock()
+	storages := make([]Storage, 0, len(p.Storages))
+	for _, storage := range p.Storages {
+		if zfsStorage, ok := storage.(*ZFSPoolStorage); ok && zfsStorage.PoolName == poolName {
+			continue
+		}
+		storages = append(storages, storage)
+	}
+	p.Storages = storages
+}
 func (p *StoragePool) GetStorageById(id string) Storage {
+	p.storagesLock.RLock()
+	defer p.storagesLock.RUnlock()
 	for _, storage := range p.Storages {
 		if storage.GetId() == id {
 			return storage
@@ -143,6 +208,102 @@ func (p *StoragePool) UpdateStorage(id string, option StorageUpdateOption) error
 	return nil
 }
 
+func (p *StoragePool) GetStorageStatus(id string) string {
+	p.statusLock.RLock()
+	defer p.statusLock.RUnlock()
+	if status, ok := p.status[id]; ok {
+		return status
+	}
+	return StorageStatusOnline
+}
+
+// missingLeafDevice report any leaf vdev whose device node is gone
+func missingLeafDevice(vt libzfs.VDevTree) bool {
+	if len(vt.Devices) == 0 && vt.Type != libzfs.VDevTypeRoot {
+		if len(vt.Path) == 0 {
+			return false
+		}
+		_, err := os.Stat(vt.Path)
+		return err != nil
+	}
+	for _, child := range vt.Devices {
+		if missingLeafDevice(child) {
+			return true
+		}
+	}
+	return false
+}
+
+func zfsStorageStatus(poolName string) string {
+	pool, err := libzfs.PoolOpen(poolName)
+	if err != nil {
+		return StorageStatusOffline
+	}
+	defer pool.Close()
+	vt, err := pool.VDevTree()
+	if err != nil {
+		return StorageStatusOffline
+	}
+	switch vt.Stat.State {
+	case libzfs.VDevStateHealthy:
+		// zfs notice removed device on next io, missing device node means pool lost redundancy already
+		if missingLeafDevice(vt) {
+			return StorageStatusDegraded
+		}
+		return StorageStatusOnline
+	case libzfs.VDevStateDegraded:
+		return StorageStatusDegraded
+	}
+	return StorageStatusOffline
+}
+
+func partStorageStatus(source string) string {
+	realPath, err := filepath.EvalSymlinks(source)
+	if err != nil {
+		return StorageStatusOffline
+	}
+	if _, err = os.Stat(realPath); err != nil {
+		return StorageStatusOffline
+	}
+	// md array keeps working with missing members
+	if degraded := readMDAttr(filepath.Base(realPath), "degraded"); len(degraded) > 0 && degraded != "0" {
+		return StorageStatusDegraded
+	}
+	return StorageStatusOnline
+}
+
+// RefreshStatus check devices of disk backed storages, return storages whose status changed
+func (p *StoragePool) RefreshStatus() map[string]string {
+	statuses := map[string]string{}
+	for _, storage := range p.List() {
+		switch s := storage.(type) {
+		case *ZFSPoolStorage:
+			statuses[s.Id] = zfsStorageStatus(s.PoolName)
+		case *DiskPartStorage:
+			statuses[s.Id] = partStorageStatus(s.Source)
+		case *LUKSPartStorage:
+			statuses[s.Id] = partStorageStatus(s.Source)
+			if statuses[s.Id] == StorageStatusOnline && s.IsLocked() {
+				statuses[s.Id] = StorageStatusLocked
+			}
+		}
+	}
+	p.statusLock.Lock()
+	defer p.statusLock.Unlock()
+	changed := map[string]string{}
+	for id, status := range statuses {
+		previous, ok := p.status[id]
+		if !ok {
+			previous = StorageStatusOnline
+		}
+		if previous != status {
+			changed[id] = status
+		}
+	}
+	p.status = statuses
+	return changed
+}
+
 type StorageUpdateOption struct {
 	Name string `json:"name"`
 }
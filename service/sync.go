@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	libzfs "github.com/bicomsystems/go-libzfs"
 	"github.com/sirupsen/logrus"
 
 	librpc "github.com/project-xpolaris/youplustoolkit/yousmb/rpc"
@@ -262,6 +263,7 @@ func SyncSmbSharesToDB() (*SyncResult, error) {
 }
 
 // SyncZFSMountsToStorage scans ZFS pools and ensures a corresponding ZFSStorage exists per pool.
+// Storages saved before but not loaded (e.g. pool imported after boot) are loaded into storage pool.
 func SyncZFSMountsToStorage() (int, int, error) {
 	created := 0
 	updated := 0
@@ -270,6 +272,7 @@ func SyncZFSMountsToStorage() (int, int, error) {
 	if err != nil {
 		return 0, 0, err
 	}
+	defer libzfs.PoolCloseAll(pools)
 	for _, pool := range pools {
 		name, e := pool.Name()
 		if e != nil {
@@ -278,14 +281,24 @@ func SyncZFSMountsToStorage() (int, int, error) {
 		var exist database.ZFSStorage
 		find := database.Instance.Where("mount_point = ?", name).First(&exist)
 		if find.Error == nil && exist.ID != "" {
-			// already exists
+			if DefaultStoragePool.GetStorageById(exist.ID) != nil {
+				// already exists
+				continue
+			}
+			s := &ZFSPoolStorage{}
+			if e = s.LoadFromSave(&exist); e != nil {
+				return created, updated, e
+			}
+			DefaultStoragePool.Add(s)
+			updated++
 			continue
 		}
 		// not exist -> create
-		_, e = CreateZFSStorage(name)
+		storage, e := CreateZFSStorage(name)
 		if e != nil {
 			return created, updated, e
 		}
+		DefaultStoragePool.Add(storage)
 		created++
 	}
 	return created, updated, nil
@@ -308,7 +321,7 @@ func ImportSmbSharesToDBStrict() (*SyncResult, error) {
 		return res, nil
 	}
 	// snapshot storages
-	storages := DefaultStoragePool.Storages
+	storages := DefaultStoragePool.List()
 	for _, section := range cfg.Sections {
 		if section == nil || section.Name == nil || section.Fields == nil {
 			continue
@@ -349,7 +362,7 @@ func ImportSmbSharesToDBStrict() (*SyncResult, error) {
 		switch matched.(type) {
 		case *ZFSPoolStorage:
 			share.ZFSStorageId = matched.GetId()
-		case *DiskPartStorage:
+		case *DiskPartStorage, *LUKSPartStorage:
 			share.PartStorageId = matched.GetId()
 		case *PathStorage:
 			share.PathStorageId = matched.GetId()
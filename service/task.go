@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"github.com/rs/xid"
 	"sync"
 	"time"
@@ -11,6 +12,12 @@ var (
 	TaskStatusRunning = "Running"
 	TaskStatusDone    = "Done"
 	TaskStatusError   = "Error"
+	TaskStatusCancel  = "Canceled"
+)
+
+var (
+	TaskNotFoundError      = errors.New("task not found")
+	TaskNotCancelableError = errors.New("task can not be canceled")
 )
 
 type Task interface {
@@ -20,6 +27,13 @@ type Task interface {
 	GetCreated() time.Time
 	GetUpdated() time.Time
 }
+
+// CancelableTask is task which can be stopped before it is done
+type CancelableTask interface {
+	Task
+	Cancel() error
+}
+
 type BaseTask struct {
 	Id           string
 	Status       string
@@ -70,3 +84,23 @@ type TaskPool struct {
 	Tasks []Task
 	sync.Mutex
 }
+
+// CancelTask stop running task with given id
+func (p *TaskPool) CancelTask(id string) error {
+	p.Lock()
+	var target Task
+	for _, task := range p.Tasks {
+		if task.GetId() == id {
+			target = task
+		}
+	}
+	p.Unlock()
+	if target == nil {
+		return TaskNotFoundError
+	}
+	cancelable, ok := target.(CancelableTask)
+	if !ok || target.GetStatus() != TaskStatusRunning {
+		return TaskNotCancelableError
+	}
+	return cancelable.Cancel()
+}
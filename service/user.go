@@ -452,6 +452,7 @@ type UserShareFolder struct {
 	Access bool                  `json:"access"`
 	Read   bool                  `json:"read"`
 	Write  bool                  `json:"write"`
+	Quota  *ShareQuota           `json:"quota,omitempty"`
 }
 
 func GetUserShareList(user *database.User) ([]*UserShareFolder, error) {
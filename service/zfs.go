@@ -99,10 +99,51 @@ func (m *ZFSManager) GetPoolsHealth() ([]ZFSPoolHealth, error) {
 	return result, nil
 }
 
+// runZpool run zpool command and return output as error message when failed
+func runZpool(args ...string) (string, error) {
+	return runZFSCommand("zpool", args...)
+}
+
+// runZfs run zfs command and return output as error message when failed
+func runZfs(args ...string) (string, error) {
+	return runZFSCommand("zfs", args...)
+}
+
+func runZFSCommand(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		message := strings.TrimSpace(string(out))
+		if len(message) == 0 {
+			return "", err
+		}
+		return "", errors.New(message)
+	}
+	return string(out), nil
+}
+
 var VdevTypeMapping = map[string]libzfs.VDevType{
 	"disk":   libzfs.VDevTypeDisk,
 	"mirror": libzfs.VDevTypeMirror,
 	"raidz":  libzfs.VDevTypeRaidz,
+	"raidz1": libzfs.VDevTypeRaidz,
+	"raidz2": libzfs.VDevTypeRaidz,
+	"raidz3": libzfs.VDevTypeRaidz,
+	"draid":  "draid",
+	"draid1": "draid",
+	"draid2": "draid",
+	"draid3": "draid",
+}
+
+// VdevParityMapping is number of disks can fail in raidz and draid vdev
+var VdevParityMapping = map[string]int{
+	"raidz":  1,
+	"raidz1": 1,
+	"raidz2": 2,
+	"raidz3": 3,
+	"draid":  1,
+	"draid1": 1,
+	"draid2": 2,
+	"draid3": 3,
 }
 
 type Node struct {
@@ -111,14 +152,21 @@ type Node struct {
 	Devices []Node `json:"devices"`
 	Spares  []Node `json:"spares"`
 	L2      []Node `json:"l2"`
+	Logs    []Node `json:"logs"`
+	Special []Node `json:"special"`
+	Dedup   []Node `json:"dedup"`
+	// Data and DistSpares is data disks per redundancy group and distributed spares of draid vdev
+	Data       int `json:"data"`
+	DistSpares int `json:"distSpares"`
 }
 
 func ConvertNodeToVDevTree(node *Node, vdev *libzfs.VDevTree) {
 	vdev.Devices = []libzfs.VDevTree{}
 	for _, dev := range node.Devices {
 		devVdev := &libzfs.VDevTree{
-			Type: VdevTypeMapping[dev.Type],
-			Path: dev.Path,
+			Type:   VdevTypeMapping[dev.Type],
+			Path:   dev.Path,
+			Parity: uint(VdevParityMapping[dev.Type]),
 		}
 		ConvertNodeToVDevTree(&dev, devVdev)
 		vdev.Devices = append(vdev.Devices, *devVdev)
@@ -133,19 +181,39 @@ func ConvertNodeToVDevTree(node *Node, vdev *libzfs.VDevTree) {
 		vdev.Spares = append(vdev.Spares, *devVdev)
 	}
 	vdev.L2Cache = []libzfs.VDevTree{}
-	for _, dev := range node.Spares {
+	for _, dev := range node.L2 {
 		devVdev := &libzfs.VDevTree{
 			Type: VdevTypeMapping[dev.Type],
 			Path: dev.Path,
 		}
 		ConvertNodeToVDevTree(&dev, devVdev)
-		vdev.Spares = append(vdev.L2Cache, *devVdev)
+		vdev.L2Cache = append(vdev.L2Cache, *devVdev)
 	}
 }
+
+// CreatePoolWithNode validate layout and create pool with zpool command,
+// which support all vdev types and allocation classes
 func (m *ZFSManager) CreatePoolWithNode(name string, rootNode Node) error {
-	rootVdev := libzfs.VDevTree{}
-	ConvertNodeToVDevTree(&rootNode, &rootVdev)
-	return m.CreatePool(name, rootVdev)
+	preview := PreviewPoolLayout(rootNode)
+	if !preview.Valid {
+		return fmt.Errorf("%w: %s", InvalidPoolLayoutError, strings.Join(preview.Errors, "; "))
+	}
+	args := []string{"create", "-m", "/" + name, name}
+	args = append(args, nodeToPoolArgs(rootNode)...)
+	if _, err := runZpool(args...); err != nil {
+		return err
+	}
+	dss, err := libzfs.DatasetOpenAll()
+	if err != nil {
+		return err
+	}
+	defer libzfs.DatasetCloseAll(dss)
+	for _, dataset := range dss {
+		if dataset.PoolName() == name {
+			dataset.Mount("", 0)
+		}
+	}
+	return nil
 }
 func (m *ZFSManager) CreateSimpleDiskPool(name string, paths ...string) error {
 	var vdev libzfs.VDevTree
@@ -222,8 +290,12 @@ func (m *ZFSManager) GetDatasetList() ([]libzfs.Dataset, error) {
 func (m *ZFSManager) CloseAllDataset(datasets []libzfs.Dataset) {
 	libzfs.DatasetCloseAll(datasets)
 }
-func (m *ZFSManager) CreateDataset(datasetPath string) (dataset libzfs.Dataset, err error) {
-	return libzfs.DatasetCreate(datasetPath, libzfs.DatasetTypeFilesystem, nil)
+func (m *ZFSManager) CreateDataset(datasetPath string, props map[string]string) (dataset libzfs.Dataset, err error) {
+	createProps, err := ToDatasetCreateProperties(props)
+	if err != nil {
+		return dataset, err
+	}
+	return libzfs.DatasetCreate(datasetPath, libzfs.DatasetTypeFilesystem, createProps)
 }
 
 func (m *ZFSManager) DeleteDataset(datasetPath string) error {
@@ -267,17 +339,37 @@ func (m *ZFSManager) DeleteSnapshot(datasetPath string, snapshotName string) err
 	if err != nil {
 		return err
 	}
+	defer dataset.Close()
+	if holds := GetSnapshotHolds(&dataset); len(holds) > 0 {
+		tags := make([]string, 0, len(holds))
+		for _, hold := range holds {
+			tags = append(tags, hold.Tag)
+		}
+		return fmt.Errorf("%w by %s, release holds first", SnapshotHeldError, strings.Join(tags, ","))
+	}
+	if clones := GetSnapshotClones(&dataset); len(clones) > 0 {
+		return fmt.Errorf("%w: %s, promote or destroy them first", SnapshotHasClonesError, strings.Join(clones, ","))
+	}
 	return dataset.Destroy(true)
 }
 
+var DatasetTypeMapping = map[string]libzfs.DatasetType{
+	"filesystem": libzfs.DatasetTypeFilesystem,
+	"volume":     libzfs.DatasetTypeVolume,
+}
+
 type DatasetQueryFilter struct {
 	Pool string `hsource:"query" hname:"pool"`
+	Type string `hsource:"query" hname:"type"`
 }
 
 func (f *DatasetQueryFilter) isValid(dataset libzfs.Dataset) bool {
 	if f.Pool != "" && f.Pool != dataset.PoolName() {
 		return false
 	}
+	if f.Type != "" && DatasetTypeMapping[f.Type] != dataset.Type {
+		return false
+	}
 	return true
 }
 func (m *ZFSManager) GetAllDataset(filter DatasetQueryFilter) ([]libzfs.Dataset, error) {
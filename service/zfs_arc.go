@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ARCStatsPath = "/proc/spl/kstat/zfs/arcstats"
+
+type ARCStats struct {
+	Size            uint64  `json:"size"`
+	Target          uint64  `json:"target"`
+	Min             uint64  `json:"min"`
+	Max             uint64  `json:"max"`
+	Hits            uint64  `json:"hits"`
+	Misses          uint64  `json:"misses"`
+	HitRatio        float64 `json:"hitRatio"`
+	MRUSize         uint64  `json:"mruSize"`
+	MFUSize         uint64  `json:"mfuSize"`
+	MRUHits         uint64  `json:"mruHits"`
+	MFUHits         uint64  `json:"mfuHits"`
+	L2Size          uint64  `json:"l2Size"`
+	L2AllocSize     uint64  `json:"l2AllocSize"`
+	L2Hits          uint64  `json:"l2Hits"`
+	L2Misses        uint64  `json:"l2Misses"`
+	L2HitRatio      float64 `json:"l2HitRatio"`
+	MemoryThrottle  uint64  `json:"memoryThrottle"`
+	NoGrow          bool    `json:"noGrow"`
+	MemoryAvailable int64   `json:"memoryAvailable"`
+}
+
+func ratio(hits uint64, misses uint64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// ReadARCStats parse arcstats kstat file, each data line is name type value
+func ReadARCStats() (*ARCStats, error) {
+	file, err := os.Open(ARCStatsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	values := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		values[fields[0]] = fields[2]
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	unsigned := func(name string) uint64 {
+		value, _ := strconv.ParseUint(values[name], 10, 64)
+		return value
+	}
+	memoryAvailable, _ := strconv.ParseInt(values["memory_available_bytes"], 10, 64)
+	stats := &ARCStats{
+		Size:            unsigned("size"),
+		Target:          unsigned("c"),
+		Min:             unsigned("c_min"),
+		Max:             unsigned("c_max"),
+		Hits:            unsigned("hits"),
+		Misses:          unsigned("misses"),
+		MRUSize:         unsigned("mru_size"),
+		MFUSize:         unsigned("mfu_size"),
+		MRUHits:         unsigned("mru_hits"),
+		MFUHits:         unsigned("mfu_hits"),
+		L2Size:          unsigned("l2_size"),
+		L2AllocSize:     unsigned("l2_asize"),
+		L2Hits:          unsigned("l2_hits"),
+		L2Misses:        unsigned("l2_misses"),
+		MemoryThrottle:  unsigned("memory_throttle_count"),
+		NoGrow:          unsigned("arc_no_grow") > 0,
+		MemoryAvailable: memoryAvailable,
+	}
+	stats.HitRatio = ratio(stats.Hits, stats.Misses)
+	stats.L2HitRatio = ratio(stats.L2Hits, stats.L2Misses)
+	return stats, nil
+}
+
+// ARCSample is arc state at sample time, hit ratios are of the sample interval
+type ARCSample struct {
+	Time            int64   `json:"time"`
+	Size            uint64  `json:"size"`
+	Target          uint64  `json:"target"`
+	MRUSize         uint64  `json:"mruSize"`
+	MFUSize         uint64  `json:"mfuSize"`
+	L2Size          uint64  `json:"l2Size"`
+	HitRatio        float64 `json:"hitRatio"`
+	L2HitRatio      float64 `json:"l2HitRatio"`
+	MemoryAvailable int64   `json:"memoryAvailable"`
+}
+
+type ARCMonitor struct {
+	History []ARCSample
+	// Interval between samples and Size of history
+	Interval time.Duration
+	Size     int
+	last     *ARCStats
+	sync.RWMutex
+}
+
+// DefaultARCMonitor keep one day of arc samples
+var DefaultARCMonitor = ARCMonitor{
+	History:  []ARCSample{},
+	Interval: 10 * time.Second,
+	Size:     8640,
+}
+
+func (m *ARCMonitor) Run() {
+	if _, err := os.Stat(ARCStatsPath); err != nil {
+		MonitorLogger.Info("arcstats not found, skip arc monitor")
+		return
+	}
+	go func() {
+		for {
+			m.sample()
+			<-time.After(m.Interval)
+		}
+	}()
+}
+
+func (m *ARCMonitor) sample() {
+	stats, err := ReadARCStats()
+	if err != nil {
+		MonitorLogger.Error(err)
+		return
+	}
+	sample := ARCSample{
+		Time:            time.Now().Unix(),
+		Size:            stats.Size,
+		Target:          stats.Target,
+		MRUSize:         stats.MRUSize,
+		MFUSize:         stats.MFUSize,
+		L2Size:          stats.L2Size,
+		MemoryAvailable: stats.MemoryAvailable,
+	}
+	m.Lock()
+	defer m.Unlock()
+	if m.last != nil && stats.Hits >= m.last.Hits && stats.Misses >= m.last.Misses {
+		sample.HitRatio = ratio(stats.Hits-m.last.Hits, stats.Misses-m.last.Misses)
+		if stats.L2Hits >= m.last.L2Hits && stats.L2Misses >= m.last.L2Misses {
+			sample.L2HitRatio = ratio(stats.L2Hits-m.last.L2Hits, stats.L2Misses-m.last.L2Misses)
+		}
+	}
+	m.last = stats
+	m.History = append(m.History, sample)
+	if len(m.History) > m.Size {
+		m.History = m.History[len(m.History)-m.Size:]
+	}
+}
+
+// GetHistory return samples newer than since
+func (m *ARCMonitor) GetHistory(since int64) []ARCSample {
+	m.RLock()
+	defer m.RUnlock()
+	result := make([]ARCSample, 0)
+	for _, sample := range m.History {
+		if sample.Time >= since {
+			result = append(result, sample)
+		}
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/sirupsen/logrus"
+)
+
+var SnapshotHasClonesError = errors.New("snapshot has dependent clones")
+
+type CloneSnapshotOption struct {
+	Dataset  string            `json:"dataset"`
+	Snapshot string            `json:"snapshot"`
+	Target   string            `json:"target"`
+	Props    map[string]string `json:"props"`
+	// Storage register clone as storage
+	Storage bool `json:"storage"`
+	// Share create share folder on clone root, storage is registered as well
+	Share     bool   `json:"share"`
+	ShareName string `json:"shareName"`
+}
+
+// CloneSnapshot create writable dataset from snapshot
+func (m *ZFSManager) CloneSnapshot(option CloneSnapshotOption) (dataset libzfs.Dataset, err error) {
+	props, err := ToDatasetCreateProperties(option.Props)
+	if err != nil {
+		return dataset, err
+	}
+	shareName := option.ShareName
+	if len(shareName) == 0 {
+		shareName = strings.ReplaceAll(option.Target, "/", "_")
+	}
+	if option.Share {
+		if err = CheckShareNameAvailable(shareName); err != nil {
+			return dataset, err
+		}
+	}
+	snapshot, err := m.openSnapshot(option.Dataset, option.Snapshot)
+	if err != nil {
+		return dataset, err
+	}
+	defer snapshot.Close()
+	dataset, err = snapshot.Clone(option.Target, props)
+	if err != nil {
+		return dataset, err
+	}
+	// clone and what is registered on it are removed when a later step fails
+	var storage Storage
+	defer func() {
+		if err == nil {
+			return
+		}
+		if storage != nil {
+			if removeErr := DefaultStoragePool.RemoveStorage(storage.GetId()); removeErr != nil {
+				logrus.Error(removeErr)
+			}
+		}
+		_ = dataset.Unmount(0)
+		dataset.Close()
+		if destroyErr := m.DeleteDataset(option.Target); destroyErr != nil {
+			logrus.Error(destroyErr)
+		}
+	}()
+	if err = dataset.Mount("", 0); err != nil {
+		return dataset, err
+	}
+	if !option.Storage && !option.Share {
+		return dataset, nil
+	}
+	if storage, err = CreateZFSStorage(option.Target); err != nil {
+		return dataset, err
+	}
+	DefaultStoragePool.Add(storage)
+	if option.Share {
+		// share is disabled until admin configures its users, like share created by CreateNewShareFolder
+		shareFolder := database.ShareFolder{
+			Name:         shareName,
+			Path:         storage.GetRootPath(),
+			Enable:       false,
+			ZFSStorageId: storage.GetId(),
+		}
+		if err = database.Instance.Save(&shareFolder).Error; err != nil {
+			return dataset, err
+		}
+		if err = SyncShareFolderOptionToSMB(&shareFolder); err != nil {
+			database.Instance.Unscoped().Delete(&database.ShareFolder{}, shareFolder.ID)
+			return dataset, err
+		}
+	}
+	return dataset, nil
+}
+
+// PromoteDataset make clone independent from its origin snapshot
+func (m *ZFSManager) PromoteDataset(datasetPath string) error {
+	dataset, err := libzfs.DatasetOpen(datasetPath)
+	if err != nil {
+		return err
+	}
+	defer dataset.Close()
+	origin := dataset.Properties[libzfs.DatasetPropOrigin].Value
+	if len(origin) == 0 || origin == "-" {
+		return fmt.Errorf("%s is not a clone", datasetPath)
+	}
+	return dataset.Promote()
+}
+
+// DestroyClone remove share folders and storage registered on clone then destroy it
+func (m *ZFSManager) DestroyClone(datasetPath string) error {
+	dataset, err := libzfs.DatasetOpen(datasetPath)
+	if err != nil {
+		return err
+	}
+	origin := dataset.Properties[libzfs.DatasetPropOrigin].Value
+	dataset.Close()
+	if len(origin) == 0 || origin == "-" {
+		return fmt.Errorf("%s is not a clone", datasetPath)
+	}
+	for _, storage := range DefaultStoragePool.List() {
+		zfsStorage, ok := storage.(*ZFSPoolStorage)
+		if !ok || zfsStorage.MountPoint != datasetPath {
+			continue
+		}
+		var shareFolders []database.ShareFolder
+		err = database.Instance.Where("zfs_storage_id = ?", zfsStorage.GetId()).Find(&shareFolders).Error
+		if err != nil {
+			return err
+		}
+		for _, shareFolder := range shareFolders {
+			if err = RemoveShare(shareFolder.ID); err != nil {
+				return err
+			}
+		}
+		if err = DefaultStoragePool.RemoveStorage(zfsStorage.GetId()); err != nil {
+			return err
+		}
+		break
+	}
+	return m.DeleteDataset(datasetPath)
+}
+
+// GetSnapshotClones return datasets cloned from snapshot
+func GetSnapshotClones(snapshot *libzfs.Dataset) []string {
+	clones, err := snapshot.Clones()
+	if err != nil {
+		return nil
+	}
+	return clones
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
+)
+
+var (
+	DiskNotFoundError = errors.New("target disk not found")
+	DiskInUseError    = errors.New("target disk is in use")
+)
+
+type ZFSPoolScanStatus struct {
+	Func      string  `json:"func"`
+	State     string  `json:"state"`
+	StartTime uint64  `json:"startTime"`
+	EndTime   uint64  `json:"endTime"`
+	ToExamine uint64  `json:"toExamine"`
+	Examined  uint64  `json:"examined"`
+	Errors    uint64  `json:"errors"`
+	Progress  float64 `json:"progress"`
+}
+
+var scanFuncNames = map[uint64]string{
+	libzfs.PoolScanNone:     "none",
+	libzfs.PoolScanScrub:    "scrub",
+	libzfs.PoolScanResilver: "resilver",
+}
+
+var scanStateNames = map[uint64]string{
+	libzfs.DSSNone:     "none",
+	libzfs.DSSScanning: "scanning",
+	libzfs.DSSFinished: "finished",
+	libzfs.DSSCanceled: "canceled",
+}
+
+func NewZFSPoolScanStatus(stat libzfs.PoolScanStat) ZFSPoolScanStatus {
+	status := ZFSPoolScanStatus{
+		Func:      scanFuncNames[stat.Func],
+		State:     scanStateNames[stat.State],
+		StartTime: stat.StartTime,
+		EndTime:   stat.EndTime,
+		ToExamine: stat.ToExamine,
+		Examined:  stat.Examined,
+		Errors:    stat.Errors,
+	}
+	if stat.ToExamine > 0 {
+		status.Progress = float64(stat.Examined) / float64(stat.ToExamine)
+	}
+	if stat.State == libzfs.DSSFinished {
+		status.Progress = 1
+	}
+	return status
+}
+
+func diskDevicePath(name string) string {
+	if strings.HasPrefix(name, "/") {
+		return name
+	}
+	return filepath.Join("/dev", name)
+}
+
+func vdevTreeContains(vt libzfs.VDevTree, names map[string]bool) bool {
+	queue := []libzfs.VDevTree{vt}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if names[cur.Name] || names[filepath.Base(cur.Path)] {
+			return true
+		}
+		queue = append(queue, cur.Devices...)
+		queue = append(queue, cur.Spares...)
+		queue = append(queue, cur.L2Cache...)
+		if cur.Logs != nil {
+			queue = append(queue, *cur.Logs)
+		}
+	}
+	return false
+}
+
+// CheckDiskAvailable make sure disk exist and is not used by pool or mounted filesystem
+func CheckDiskAvailable(name string) error {
+	disk := GetDiskByName(filepath.Base(name))
+	if disk == nil {
+		return DiskNotFoundError
+	}
+	names := map[string]bool{disk.Name: true}
+	for _, part := range disk.Parts {
+		if len(part.MountPoint) > 0 {
+			return fmt.Errorf("%w: %s is mounted on %s", DiskInUseError, part.Name, part.MountPoint)
+		}
+		names[part.Name] = true
+	}
+	poolName, err := poolMemberOf(names)
+	if err != nil {
+		return err
+	}
+	if len(poolName) > 0 {
+		return fmt.Errorf("%w: %s is member of pool %s", DiskInUseError, disk.Name, poolName)
+	}
+	if array := mdMemberOf(names); len(array) > 0 {
+		return fmt.Errorf("%w: %s is member of raid array %s", DiskInUseError, disk.Name, array)
+	}
+	return nil
+}
+
+func collectNodePaths(node Node) []string {
+	paths := make([]string, 0)
+	if len(node.Path) > 0 {
+		paths = append(paths, node.Path)
+	}
+	for _, children := range [][]Node{node.Devices, node.Spares, node.L2, node.Logs, node.Special, node.Dedup} {
+		for _, child := range children {
+			paths = append(paths, collectNodePaths(child)...)
+		}
+	}
+	return paths
+}
+
+// vdevSpec return vdev type argument of zpool command, draid config is like draid2:4d:1s
+func vdevSpec(node Node) string {
+	if !strings.HasPrefix(node.Type, "draid") {
+		return node.Type
+	}
+	spec := node.Type
+	if node.Data > 0 {
+		spec += fmt.Sprintf(":%dd", node.Data)
+	}
+	if node.DistSpares > 0 {
+		spec += fmt.Sprintf(":%ds", node.DistSpares)
+	}
+	return spec
+}
+
+func nodeToZpoolArgs(nodes []Node) []string {
+	args := make([]string, 0)
+	for _, node := range nodes {
+		if node.Type == "" || node.Type == libzfs.VDevTypeDisk {
+			args = append(args, diskDevicePath(node.Path))
+			continue
+		}
+		args = append(args, vdevSpec(node))
+		for _, child := range node.Devices {
+			args = append(args, diskDevicePath(child.Path))
+		}
+	}
+	return args
+}
+
+// nodeToPoolArgs return vdev arguments of root node including allocation classes, spares and cache
+func nodeToPoolArgs(node Node) []string {
+	args := nodeToZpoolArgs(node.Devices)
+	classes := []struct {
+		Keyword string
+		Nodes   []Node
+	}{
+		{Keyword: "special", Nodes: node.Special},
+		{Keyword: "dedup", Nodes: node.Dedup},
+		{Keyword: "log", Nodes: node.Logs},
+		{Keyword: "cache", Nodes: node.L2},
+		{Keyword: "spare", Nodes: node.Spares},
+	}
+	for _, class := range classes {
+		if len(class.Nodes) > 0 {
+			args = append(args, class.Keyword)
+			args = append(args, nodeToZpoolArgs(class.Nodes)...)
+		}
+	}
+	return args
+}
+
+func (m *ZFSManager) openPool(name string) (libzfs.Pool, error) {
+	pool, err := libzfs.PoolOpen(name)
+	if err != nil {
+		return pool, PoolNotFoundError
+	}
+	return pool, nil
+}
+
+// ReplaceDevice replace device in pool with new disk, resilver will start in background
+func (m *ZFSManager) ReplaceDevice(poolName string, device string, newDisk string, force bool) error {
+	pool, err := m.openPool(poolName)
+	if err != nil {
+		return err
+	}
+	pool.Close()
+	args := []string{"replace"}
+	if force {
+		args = append(args, "-f")
+	}
+	args = append(args, poolName, device)
+	if len(newDisk) > 0 {
+		if err = CheckDiskAvailable(newDisk); err != nil {
+			return err
+		}
+		args = append(args, diskDevicePath(newDisk))
+	}
+	_, err = runZpool(args...)
+	return err
+}
+
+// AttachDevice attach new disk to device, turn single disk into mirror or extend mirror
+func (m *ZFSManager) AttachDevice(poolName string, device string, newDisk string, force bool) error {
+	pool, err := m.openPool(poolName)
+	if err != nil {
+		return err
+	}
+	pool.Close()
+	if err = CheckDiskAvailable(newDisk); err != nil {
+		return err
+	}
+	args := []string{"attach"}
+	if force {
+		args = append(args, "-f")
+	}
+	args = append(args, poolName, device, diskDevicePath(newDisk))
+	_, err = runZpool(args...)
+	return err
+}
+
+func (m *ZFSManager) DetachDevice(poolName string, device string) error {
+	pool, err := m.openPool(poolName)
+	if err != nil {
+		return err
+	}
+	pool.Close()
+	_, err = runZpool("detach", poolName, device)
+	return err
+}
+
+func (m *ZFSManager) OnlineDevice(poolName string, device string, expand bool) error {
+	pool, err := m.openPool(poolName)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	return pool.Online(expand, device)
+}
+
+func (m *ZFSManager) OfflineDevice(poolName string, device string, temporary bool) error {
+	pool, err := m.openPool(poolName)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	if temporary {
+		return pool.OfflineTemp(false, device)
+	}
+	return pool.Offline(false, device)
+}
+
+// AddVdevs add top-level vdevs, allocation classes, spares, cache and log devices in node to pool
+func (m *ZFSManager) AddVdevs(poolName string, node Node, force bool) error {
+	pool, err := m.openPool(poolName)
+	if err != nil {
+		return err
+	}
+	pool.Close()
+	paths := collectNodePaths(node)
+	if len(paths) == 0 {
+		return errors.New("no device to add")
+	}
+	for _, path := range paths {
+		if err = CheckDiskAvailable(path); err != nil {
+			return err
+		}
+	}
+	args := []string{"add"}
+	if force {
+		args = append(args, "-f")
+	}
+	args = append(args, poolName)
+	args = append(args, nodeToPoolArgs(node)...)
+	_, err = runZpool(args...)
+	return err
+}
+
+// GetPoolScanStatus return scrub or resilver progress of pool
+func (m *ZFSManager) GetPoolScanStatus(poolName string) (*ZFSPoolScanStatus, error) {
+	pool, err := m.openPool(poolName)
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Close()
+	vt, err := pool.VDevTree()
+	if err != nil {
+		return nil, err
+	}
+	status := NewZFSPoolScanStatus(vt.ScanStat)
+	return &status, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var diffChangeMapping = map[string]string{
+	"+": "added",
+	"-": "removed",
+	"M": "modified",
+	"R": "renamed",
+}
+
+var diffFileTypeMapping = map[string]string{
+	"F": "file",
+	"/": "directory",
+	"@": "symlink",
+	"B": "block",
+	"C": "char",
+	"|": "pipe",
+	"=": "socket",
+	">": "door",
+	"P": "port",
+}
+
+type SnapshotDiffEntry struct {
+	Change  string `json:"change"`
+	Type    string `json:"type"`
+	Path    string `json:"path"`
+	NewPath string `json:"newPath,omitempty"`
+}
+
+type SnapshotDiffOption struct {
+	Dataset string
+	From    string
+	// To is snapshot name to compare with, empty means compare with live dataset
+	To       string
+	Page     int
+	PageSize int
+}
+
+// unescapeDiffPath decode octal escape like \0040 written by zfs diff for special characters
+func unescapeDiffPath(path string) string {
+	if !strings.Contains(path, "\\") {
+		return path
+	}
+	var builder strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+4 < len(path) {
+			if code, err := strconv.ParseUint(path[i+1:i+5], 8, 8); err == nil {
+				builder.WriteByte(byte(code))
+				i += 4
+				continue
+			}
+		}
+		builder.WriteByte(path[i])
+	}
+	return builder.String()
+}
+
+func parseSnapshotDiffLine(line string) (SnapshotDiffEntry, bool) {
+	fields := strings.Split(line, "\t")
+	if len(fields) < 3 {
+		return SnapshotDiffEntry{}, false
+	}
+	change, ok := diffChangeMapping[fields[0]]
+	if !ok {
+		return SnapshotDiffEntry{}, false
+	}
+	entry := SnapshotDiffEntry{
+		Change: change,
+		Type:   diffFileTypeMapping[fields[1]],
+		Path:   unescapeDiffPath(fields[2]),
+	}
+	if len(entry.Type) == 0 {
+		entry.Type = "unknown"
+	}
+	if len(fields) > 3 {
+		entry.NewPath = unescapeDiffPath(fields[3])
+	}
+	return entry, true
+}
+
+// maxCachedSnapshotDiffs is number of diff results kept for paging, liveSnapshotDiffTTL is how long
+// diff against live dataset is reused since live dataset keeps changing
+const (
+	maxCachedSnapshotDiffs = 8
+	liveSnapshotDiffTTL    = time.Minute
+)
+
+type snapshotDiffCacheItem struct {
+	Key     string
+	Entries []SnapshotDiffEntry
+	Created time.Time
+	Live    bool
+}
+
+// snapshotDiffCache keep recent diff results so paging does not run zfs diff for every page
+var snapshotDiffCache = struct {
+	Items []*snapshotDiffCacheItem
+	sync.Mutex
+}{}
+
+func getCachedSnapshotDiff(key string) []SnapshotDiffEntry {
+	snapshotDiffCache.Lock()
+	defer snapshotDiffCache.Unlock()
+	for _, item := range snapshotDiffCache.Items {
+		if item.Key != key {
+			continue
+		}
+		if item.Live && time.Since(item.Created) > liveSnapshotDiffTTL {
+			return nil
+		}
+		return item.Entries
+	}
+	return nil
+}
+
+func putCachedSnapshotDiff(item *snapshotDiffCacheItem) {
+	snapshotDiffCache.Lock()
+	defer snapshotDiffCache.Unlock()
+	items := []*snapshotDiffCacheItem{item}
+	for _, cached := range snapshotDiffCache.Items {
+		if cached.Key != item.Key && len(items) < maxCachedSnapshotDiffs {
+			items = append(items, cached)
+		}
+	}
+	snapshotDiffCache.Items = items
+}
+
+// resolveDiffDataset accept dataset name or path on dataset like grpc api does
+func (m *ZFSManager) resolveDiffDataset(dataset string) (string, error) {
+	if !strings.HasPrefix(dataset, "/") {
+		return dataset, nil
+	}
+	datasetPath, _, err := m.GetDatasetPathByMountPath(filepath.Clean(dataset))
+	if err != nil {
+		return "", err
+	}
+	if len(datasetPath) == 0 {
+		return "", NotZFSPathError
+	}
+	return datasetPath, nil
+}
+
+// readSnapshotDiff run zfs diff, result is cached by guid of snapshots so recreated snapshot is not mixed up
+func readSnapshotDiff(from string, to string) ([]SnapshotDiffEntry, error) {
+	live := !strings.Contains(to, "@")
+	guidArgs := []string{"get", "-Hp", "-o", "value", "guid", from}
+	if !live {
+		guidArgs = append(guidArgs, to)
+	}
+	guids, err := runZfs(guidArgs...)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%s>%s:%s", from, to, strings.Join(strings.Fields(guids), ","))
+	if entries := getCachedSnapshotDiff(key); entries != nil {
+		return entries, nil
+	}
+	out, err := runZfs("diff", "-FH", from, to)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]SnapshotDiffEntry, 0)
+	for _, line := range strings.Split(out, "\n") {
+		if entry, ok := parseSnapshotDiffLine(line); ok {
+			entries = append(entries, entry)
+		}
+	}
+	putCachedSnapshotDiff(&snapshotDiffCacheItem{Key: key, Entries: entries, Created: time.Now(), Live: live})
+	return entries, nil
+}
+
+// GetSnapshotDiff list changed files between two snapshots or between snapshot and live dataset,
+// dataset is dataset name or path on dataset, return entries of requested page and total count
+func (m *ZFSManager) GetSnapshotDiff(option SnapshotDiffOption) ([]SnapshotDiffEntry, int, error) {
+	if len(option.From) == 0 {
+		return nil, 0, fmt.Errorf("snapshot to compare from is required")
+	}
+	dataset, err := m.resolveDiffDataset(option.Dataset)
+	if err != nil {
+		return nil, 0, err
+	}
+	to := dataset
+	if len(option.To) > 0 {
+		to = fmt.Sprintf("%s@%s", dataset, option.To)
+	}
+	entries, err := readSnapshotDiff(fmt.Sprintf("%s@%s", dataset, option.From), to)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := len(entries)
+	if option.PageSize <= 0 {
+		return entries, total, nil
+	}
+	if option.Page < 1 {
+		option.Page = 1
+	}
+	start := (option.Page - 1) * option.PageSize
+	if start >= total {
+		return []SnapshotDiffEntry{}, total, nil
+	}
+	end := start + option.PageSize
+	if end > total {
+		end = total
+	}
+	return entries[start:end], total, nil
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
+	"github.com/projectxpolaris/youplus/config"
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	DatasetLockedError = errors.New("dataset is locked")
+	InvalidKeyError    = errors.New("invalid encryption key")
+)
+
+const (
+	KeyStatusAvailable   = "available"
+	KeyStatusUnavailable = "unavailable"
+)
+
+var EncryptionLogger = logrus.New().WithFields(logrus.Fields{
+	"scope": "Encryption",
+})
+
+type DatasetEncryptionOption struct {
+	// Encryption is cipher suite, on or empty use default
+	Encryption  string `json:"encryption"`
+	KeyFormat   string `json:"keyFormat"`
+	KeyLocation string `json:"keyLocation"`
+	Key         string `json:"key"`
+}
+
+// keyFilePath return file path of keylocation like file:///path/to/key
+func keyFilePath(keyLocation string) string {
+	return strings.TrimPrefix(keyLocation, "file://")
+}
+
+// DatasetKeyDir is directory key files given in keylocation must be in
+func DatasetKeyDir() string {
+	return filepath.Join(config.ConfigDir(), "keys")
+}
+
+// datasetKeyFile check key file is in key directory after resolving symlinks, return cleaned path
+func datasetKeyFile(keyFile string) (string, error) {
+	path := filepath.Clean(keyFile)
+	if !filepath.IsAbs(path) || !strings.HasPrefix(path, DatasetKeyDir()+"/") {
+		return "", fmt.Errorf("%w: key file must be in %s", InvalidKeyError, DatasetKeyDir())
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if os.IsNotExist(err) {
+		return path, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	keyDir, err := filepath.EvalSymlinks(DatasetKeyDir())
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(realPath, keyDir+"/") {
+		return "", fmt.Errorf("%w: key file must be in %s", InvalidKeyError, DatasetKeyDir())
+	}
+	return path, nil
+}
+
+func (o *DatasetEncryptionOption) validate() error {
+	if len(o.KeyLocation) == 0 {
+		o.KeyLocation = "prompt"
+	}
+	if o.KeyLocation != "prompt" && !strings.HasPrefix(o.KeyLocation, "file:///") {
+		return fmt.Errorf("%w: keylocation must be prompt or file:///path", InvalidKeyError)
+	}
+	if o.KeyLocation == "prompt" || len(o.Key) > 0 {
+		switch o.KeyFormat {
+		case "passphrase":
+			if len(o.Key) < 8 || len(o.Key) > 512 {
+				return fmt.Errorf("%w: passphrase must be 8 to 512 characters", InvalidKeyError)
+			}
+		case "hex":
+			if len(o.Key) != 64 {
+				return fmt.Errorf("%w: hex key must be 64 characters", InvalidKeyError)
+			}
+		case "raw":
+			if len(o.Key) != 32 {
+				return fmt.Errorf("%w: raw key must be 32 bytes", InvalidKeyError)
+			}
+		default:
+			return fmt.Errorf("%w: keyformat must be passphrase, hex or raw", InvalidKeyError)
+		}
+	}
+	if strings.HasPrefix(o.KeyLocation, "file:///") {
+		path, err := datasetKeyFile(keyFilePath(o.KeyLocation))
+		if err != nil {
+			return err
+		}
+		o.KeyLocation = "file://" + path
+		if len(o.Key) == 0 {
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("%w: key file %s not found", InvalidKeyError, path)
+			}
+		}
+	}
+	return nil
+}
+
+// writesKeyFile report key from request is saved to key file of keylocation
+func (o *DatasetEncryptionOption) writesKeyFile() bool {
+	return strings.HasPrefix(o.KeyLocation, "file:///") && len(o.Key) > 0
+}
+
+// zfsKeyLocation return keylocation passed to zfs, new key file is not in place yet so key is prompted from stdin
+func (o *DatasetEncryptionOption) zfsKeyLocation() string {
+	if o.writesKeyFile() {
+		return "prompt"
+	}
+	return o.KeyLocation
+}
+
+// stdin return key for command input, key from existing key file is read by zfs itself
+func (o *DatasetEncryptionOption) stdin() string {
+	if o.KeyLocation == "prompt" || o.writesKeyFile() {
+		return o.Key
+	}
+	return ""
+}
+
+// stageKeyFile write key to temporary file in key directory, it replaces key file only after zfs accepted the key
+func (o *DatasetEncryptionOption) stageKeyFile() (string, error) {
+	if !o.writesKeyFile() {
+		return "", nil
+	}
+	return stageDatasetKey(o.Key)
+}
+
+// stageDatasetKey write key to temporary file in key directory
+func stageDatasetKey(key string) (string, error) {
+	if err := os.MkdirAll(DatasetKeyDir(), 0700); err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp(DatasetKeyDir(), ".key-")
+	if err != nil {
+		return "", err
+	}
+	_, err = file.WriteString(key)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// commitKeyFile move staged key file over key file and point keylocation of dataset to it
+func (o *DatasetEncryptionOption) commitKeyFile(datasetPath string, staged string) error {
+	if len(staged) == 0 {
+		return nil
+	}
+	if err := os.Rename(staged, keyFilePath(o.KeyLocation)); err != nil {
+		return err
+	}
+	_, err := runZfs("set", fmt.Sprintf("keylocation=%s", o.KeyLocation), datasetPath)
+	return err
+}
+
+func runZfsWithInput(input string, args ...string) error {
+	cmd := exec.Command("zfs", args...)
+	cmd.Stdin = strings.NewReader(input)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		message := strings.TrimSpace(string(out))
+		if len(message) == 0 {
+			return err
+		}
+		return errors.New(message)
+	}
+	return nil
+}
+
+func (m *ZFSManager) CreateEncryptedDataset(datasetPath string, props map[string]string, option DatasetEncryptionOption) (dataset libzfs.Dataset, err error) {
+	if err = option.validate(); err != nil {
+		return dataset, err
+	}
+	if len(option.Encryption) == 0 {
+		option.Encryption = "on"
+	}
+	args := []string{
+		"create",
+		"-o", fmt.Sprintf("encryption=%s", option.Encryption),
+		"-o", fmt.Sprintf("keyformat=%s", option.KeyFormat),
+		"-o", fmt.Sprintf("keylocation=%s", option.zfsKeyLocation()),
+	}
+	for name, value := range props {
+		if _, err = ValidateDatasetProperty(name, value); err != nil {
+			return dataset, err
+		}
+		args = append(args, "-o", fmt.Sprintf("%s=%s", name, value))
+	}
+	args = append(args, datasetPath)
+	staged, err := option.stageKeyFile()
+	if err != nil {
+		return dataset, err
+	}
+	if err = runZfsWithInput(option.stdin(), args...); err != nil {
+		os.Remove(staged)
+		return dataset, err
+	}
+	if err = option.commitKeyFile(datasetPath, staged); err != nil {
+		os.Remove(staged)
+		return dataset, err
+	}
+	return libzfs.DatasetOpen(datasetPath)
+}
+
+// LoadDatasetKey load key of encrypted dataset and mount it, keyFile override keylocation of dataset
+func (m *ZFSManager) LoadDatasetKey(datasetPath string, key string, keyFile string) error {
+	args := []string{"load-key"}
+	if len(keyFile) > 0 {
+		path, err := datasetKeyFile(keyFile)
+		if err != nil {
+			return err
+		}
+		args = append(args, "-L", "file://"+path)
+	}
+	args = append(args, datasetPath)
+	if err := runZfsWithInput(key, args...); err != nil {
+		return err
+	}
+	if _, err := runZfs("mount", datasetPath); err != nil {
+		return err
+	}
+	m.syncDatasetShareFolders(datasetPath)
+	return nil
+}
+
+// UnloadDatasetKey unmount encrypted dataset and unload its key
+func (m *ZFSManager) UnloadDatasetKey(datasetPath string) error {
+	if _, err := runZfs("unmount", datasetPath); err != nil {
+		return err
+	}
+	if _, err := runZfs("unload-key", datasetPath); err != nil {
+		return err
+	}
+	m.syncDatasetShareFolders(datasetPath)
+	return nil
+}
+
+func (m *ZFSManager) ChangeDatasetKey(datasetPath string, option DatasetEncryptionOption) error {
+	if err := option.validate(); err != nil {
+		return err
+	}
+	staged, err := option.stageKeyFile()
+	if err != nil {
+		return err
+	}
+	err = runZfsWithInput(
+		option.stdin(),
+		"change-key",
+		"-o", fmt.Sprintf("keyformat=%s", option.KeyFormat),
+		"-o", fmt.Sprintf("keylocation=%s", option.zfsKeyLocation()),
+		datasetPath,
+	)
+	if err == nil {
+		err = option.commitKeyFile(datasetPath, staged)
+	}
+	if err != nil {
+		os.Remove(staged)
+		return err
+	}
+	return nil
+}
+
+func (m *ZFSManager) IsDatasetLocked(datasetPath string) (bool, error) {
+	dataset, err := libzfs.DatasetOpenSingle(datasetPath)
+	if err != nil {
+		return false, err
+	}
+	defer dataset.Close()
+	keyStatus, err := dataset.GetProperty(libzfs.DatasetPropKeyStatus)
+	if err != nil {
+		return false, err
+	}
+	return keyStatus.Value == KeyStatusUnavailable, nil
+}
+
+// IsPathLocked check path is on encrypted dataset which key is not loaded or on locked luks storage
+func IsPathLocked(path string) bool {
+	if luksStorage := luksStorageOfPath(path); luksStorage != nil {
+		return luksStorage.IsLocked()
+	}
+	datasetPath, _, err := DefaultZFSManager.GetDatasetPathByMountPath(path)
+	if err != nil || len(datasetPath) == 0 {
+		return false
+	}
+	locked, err := DefaultZFSManager.IsDatasetLocked(datasetPath)
+	if err != nil {
+		return false
+	}
+	return locked
+}
+
+// syncDatasetShareFolders update smb config of share folders on dataset after lock state changed
+func (m *ZFSManager) syncDatasetShareFolders(datasetPath string) {
+	dataset, err := libzfs.DatasetOpenSingle(datasetPath)
+	if err != nil {
+		EncryptionLogger.Error(err)
+		return
+	}
+	mountPoint := dataset.Properties[libzfs.DatasetPropMountpoint].Value
+	dataset.Close()
+	syncShareFoldersOfPath(mountPoint)
+}
+
+// syncShareFoldersOfPath update smb config of share folders under mount point
+func syncShareFoldersOfPath(mountPoint string) {
+	folders, err := GetShareFolders()
+	if err != nil {
+		EncryptionLogger.Error(err)
+		return
+	}
+	for _, folder := range folders {
+		if folder.Path != mountPoint && !strings.HasPrefix(folder.Path, strings.TrimSuffix(mountPoint, "/")+"/") {
+			continue
+		}
+		if err = SyncShareFolderOptionToSMB(folder); err != nil {
+			EncryptionLogger.Error(err)
+		}
+	}
+}
+
+// SetDatasetAutoUnlock save key file used to unlock dataset at boot, key is written to key file of dataset in key directory
+func SetDatasetAutoUnlock(datasetPath string, key string, keyFile string) error {
+	staged := ""
+	if len(key) > 0 {
+		var err error
+		if staged, err = stageDatasetKey(key); err != nil {
+			return err
+		}
+		defer os.Remove(staged)
+		keyFile = filepath.Join(DatasetKeyDir(), strings.ReplaceAll(datasetPath, "/", "_")+".key")
+	}
+	path, err := datasetKeyFile(keyFile)
+	if err != nil {
+		return err
+	}
+	checkFile := path
+	if len(staged) > 0 {
+		checkFile = staged
+	} else if _, err = os.Stat(path); err != nil {
+		return fmt.Errorf("%w: key file %s not found", InvalidKeyError, path)
+	}
+	// key of locked dataset can be verified by dry run
+	if locked, _ := DefaultZFSManager.IsDatasetLocked(datasetPath); locked {
+		if _, err = runZfs("load-key", "-n", "-L", "file://"+checkFile, datasetPath); err != nil {
+			return fmt.Errorf("%w: %s", InvalidKeyError, err.Error())
+		}
+	}
+	if len(staged) > 0 {
+		if err = os.Rename(staged, path); err != nil {
+			return err
+		}
+	}
+	var datasetKey database.DatasetKey
+	database.Instance.Where("dataset = ?", datasetPath).First(&datasetKey)
+	datasetKey.Dataset = datasetPath
+	datasetKey.KeyFile = path
+	return database.Instance.Save(&datasetKey).Error
+}
+
+func RemoveDatasetAutoUnlock(datasetPath string) error {
+	return database.Instance.Unscoped().Where("dataset = ?", datasetPath).Delete(&database.DatasetKey{}).Error
+}
+
+// UnlockDatasetsAtBoot load keys of datasets configured with key file
+func UnlockDatasetsAtBoot() error {
+	var keys []database.DatasetKey
+	err := database.Instance.Find(&keys).Error
+	if err != nil {
+		return err
+	}
+	for _, datasetKey := range keys {
+		locked, err := DefaultZFSManager.IsDatasetLocked(datasetKey.Dataset)
+		if err != nil {
+			EncryptionLogger.Error(err)
+			continue
+		}
+		if !locked {
+			continue
+		}
+		err = DefaultZFSManager.LoadDatasetKey(datasetKey.Dataset, "", datasetKey.KeyFile)
+		if err != nil {
+			EncryptionLogger.WithField("dataset", datasetKey.Dataset).Error(err)
+			continue
+		}
+		EncryptionLogger.WithField("dataset", datasetKey.Dataset).Info("dataset unlocked")
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	EventLevelInfo    = "info"
+	EventLevelWarning = "warning"
+	EventLevelError   = "error"
+)
+
+// PoolHealthChangeClass is class of event created when pool health changed
+const PoolHealthChangeClass = "youplus.pool.health"
+
+// maxStoredZFSEvents is number of events kept in database
+const maxStoredZFSEvents = 5000
+
+var ZFSEventLogger = logrus.New().WithFields(logrus.Fields{
+	"scope": "ZFSEvent",
+})
+
+var DefaultZFSEventWatcher = ZFSEventWatcher{
+	PoolHealth: map[string]string{},
+}
+
+// zfsEventLevels is level of recorded event class prefix, classes not listed are ignored
+var zfsEventLevels = []struct {
+	Prefix string
+	Level  string
+}{
+	{Prefix: "ereport.fs.zfs.checksum", Level: EventLevelWarning},
+	{Prefix: "ereport.fs.zfs.io", Level: EventLevelWarning},
+	{Prefix: "ereport.fs.zfs.data", Level: EventLevelError},
+	{Prefix: "ereport.fs.zfs.vdev", Level: EventLevelError},
+	{Prefix: "ereport.fs.zfs.probe_failure", Level: EventLevelError},
+	{Prefix: "ereport.fs.zfs", Level: EventLevelWarning},
+	{Prefix: "resource.fs.zfs.statechange", Level: EventLevelWarning},
+	{Prefix: "resource.fs.zfs.removed", Level: EventLevelWarning},
+	{Prefix: "sysevent.fs.zfs.scrub_start", Level: EventLevelInfo},
+	{Prefix: "sysevent.fs.zfs.scrub_finish", Level: EventLevelInfo},
+	{Prefix: "sysevent.fs.zfs.scrub_abort", Level: EventLevelWarning},
+	{Prefix: "sysevent.fs.zfs.resilver_start", Level: EventLevelInfo},
+	{Prefix: "sysevent.fs.zfs.resilver_finish", Level: EventLevelInfo},
+	{Prefix: "sysevent.fs.zfs.vdev_remove", Level: EventLevelWarning},
+	{Prefix: "sysevent.fs.zfs.vdev_spare", Level: EventLevelWarning},
+	{Prefix: "sysevent.fs.zfs.vdev_online", Level: EventLevelInfo},
+	{Prefix: "sysevent.fs.zfs.pool_import", Level: EventLevelInfo},
+	{Prefix: "sysevent.fs.zfs.pool_destroy", Level: EventLevelInfo},
+}
+
+func zfsEventLevel(class string) (string, bool) {
+	for _, item := range zfsEventLevels {
+		if strings.HasPrefix(class, item.Prefix) {
+			return item.Level, true
+		}
+	}
+	return "", false
+}
+
+type ZFSEventListener func(event *database.ZFSEvent)
+
+// ZFSEventWatcher follow zpool events and pool health, record them and notify listeners
+type ZFSEventWatcher struct {
+	Listeners  []ZFSEventListener
+	PoolHealth map[string]string
+	sync.Mutex
+}
+
+func (w *ZFSEventWatcher) AddListener(listener ZFSEventListener) {
+	w.Lock()
+	defer w.Unlock()
+	w.Listeners = append(w.Listeners, listener)
+}
+
+func (w *ZFSEventWatcher) emit(event *database.ZFSEvent) {
+	err := database.Instance.Create(event).Error
+	if err != nil {
+		ZFSEventLogger.Error(err)
+	} else if event.ID > maxStoredZFSEvents {
+		database.Instance.Unscoped().Where("id <= ?", event.ID-maxStoredZFSEvents).Delete(&database.ZFSEvent{})
+	}
+	w.Lock()
+	listeners := append([]ZFSEventListener{}, w.Listeners...)
+	w.Unlock()
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+func (w *ZFSEventWatcher) Run() {
+	go w.followEvents()
+	go func() {
+		for {
+			w.checkPoolHealth()
+			<-time.After(10 * time.Second)
+		}
+	}()
+}
+
+// countEvents return number of events already in zpool event log
+func countEvents() (int, error) {
+	out, err := runZpool("events", "-H")
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, line := range strings.Split(out, "\n") {
+		if len(strings.TrimSpace(line)) > 0 {
+			count += 1
+		}
+	}
+	return count, nil
+}
+
+type zfsEventRecord struct {
+	Class  string
+	Fields map[string]string
+}
+
+// parseEventValue read string value of verbose event field, numeric and nested values are ignored
+func parseEventValue(value string) (string, bool) {
+	if !strings.HasPrefix(value, "\"") {
+		return "", false
+	}
+	end := strings.LastIndex(value, "\"")
+	if end <= 0 {
+		return "", false
+	}
+	return value[1:end], true
+}
+
+func (w *ZFSEventWatcher) followEvents() {
+	for {
+		skip, err := countEvents()
+		if err != nil {
+			ZFSEventLogger.Error(err)
+			<-time.After(30 * time.Second)
+			continue
+		}
+		err = w.readEvents(skip)
+		if err != nil {
+			ZFSEventLogger.Error(err)
+		}
+		<-time.After(30 * time.Second)
+	}
+}
+
+func (w *ZFSEventWatcher) readEvents(skip int) error {
+	cmd := exec.Command("zpool", "events", "-H", "-f", "-v")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(stdout)
+	var current *zfsEventRecord
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if skip > 0 {
+			skip -= 1
+		} else {
+			w.handleEvent(current)
+		}
+		current = nil
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			// header line is time followed by class
+			flush()
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			current = &zfsEventRecord{Class: fields[len(fields)-1], Fields: map[string]string{}}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimSpace(line), " = ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if _, exist := current.Fields[parts[0]]; exist {
+			continue
+		}
+		if value, ok := parseEventValue(parts[1]); ok {
+			current.Fields[parts[0]] = value
+		}
+	}
+	flush()
+	return cmd.Wait()
+}
+
+func (w *ZFSEventWatcher) handleEvent(record *zfsEventRecord) {
+	level, ok := zfsEventLevel(record.Class)
+	if !ok {
+		return
+	}
+	event := &database.ZFSEvent{
+		Class: record.Class,
+		Pool:  record.Fields["pool"],
+		Vdev:  record.Fields["vdev_path"],
+		State: record.Fields["vdev_state"],
+		Level: level,
+		Time:  time.Now(),
+	}
+	name := record.Class[strings.LastIndex(record.Class, ".")+1:]
+	switch {
+	case len(event.Vdev) > 0 && len(event.State) > 0:
+		event.Message = fmt.Sprintf("%s: %s on %s is %s", event.Pool, name, event.Vdev, event.State)
+	case len(event.Vdev) > 0:
+		event.Message = fmt.Sprintf("%s: %s on %s", event.Pool, name, event.Vdev)
+	default:
+		event.Message = fmt.Sprintf("%s: %s", event.Pool, name)
+	}
+	w.emit(event)
+	// state of pool may changed, check it without waiting
+	if level != EventLevelInfo {
+		go w.checkPoolHealth()
+	}
+}
+
+func (w *ZFSEventWatcher) checkPoolHealth() {
+	pools, err := DefaultZFSManager.GetPoolsHealth()
+	if err != nil {
+		return
+	}
+	changed := make([]*database.ZFSEvent, 0)
+	w.Lock()
+	for _, pool := range pools {
+		before, exist := w.PoolHealth[pool.Name]
+		w.PoolHealth[pool.Name] = pool.Health
+		if before == pool.Health || (!exist && pool.Health == "ONLINE") {
+			continue
+		}
+		level := EventLevelError
+		if pool.Health == "ONLINE" {
+			level = EventLevelInfo
+		}
+		// pool first seen unhealthy, e.g. already degraded at boot
+		message := fmt.Sprintf("pool %s is %s", pool.Name, pool.Health)
+		if exist {
+			message = fmt.Sprintf("pool %s changed from %s to %s", pool.Name, before, pool.Health)
+		}
+		changed = append(changed, &database.ZFSEvent{
+			Class:   PoolHealthChangeClass,
+			Pool:    pool.Name,
+			State:   pool.Health,
+			Level:   level,
+			Message: message,
+			Time:    time.Now(),
+		})
+	}
+	w.Unlock()
+	for _, event := range changed {
+		w.emit(event)
+	}
+}
+
+type ZFSEventQueryFilter struct {
+	Pool     string `hsource:"query" hname:"pool"`
+	Level    string `hsource:"query" hname:"level"`
+	Alert    bool   `hsource:"query" hname:"alert"`
+	Page     int    `hsource:"query" hname:"page"`
+	PageSize int    `hsource:"query" hname:"pageSize"`
+}
+
+// GetZFSEvents return recorded events, alert filter return unacknowledged warning and error events
+func GetZFSEvents(filter ZFSEventQueryFilter) ([]database.ZFSEvent, int64, error) {
+	query := database.Instance.Model(&database.ZFSEvent{})
+	if len(filter.Pool) > 0 {
+		query = query.Where("pool = ?", filter.Pool)
+	}
+	if len(filter.Level) > 0 {
+		query = query.Where("level = ?", filter.Level)
+	}
+	if filter.Alert {
+		query = query.Where("level in ? and acknowledged = ?", []string{EventLevelWarning, EventLevelError}, false)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+	events := make([]database.ZFSEvent, 0)
+	err = query.Order("id desc").Offset((filter.Page - 1) * filter.PageSize).Limit(filter.PageSize).Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, count, nil
+}
+
+func AcknowledgeZFSEvents(ids []uint) error {
+	query := database.Instance.Model(&database.ZFSEvent{})
+	if len(ids) > 0 {
+		query = query.Where("id in ?", ids)
+	} else {
+		query = query.Where("acknowledged = ?", false)
+	}
+	return query.Update("acknowledged", true).Error
+}
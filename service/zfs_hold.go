@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
+)
+
+var SnapshotHeldError = errors.New("snapshot is held")
+
+type SnapshotHold struct {
+	Tag     string    `json:"tag"`
+	Created time.Time `json:"created"`
+}
+
+func (m *ZFSManager) openSnapshot(datasetPath string, snapshotName string) (libzfs.Dataset, error) {
+	snapshot, err := libzfs.DatasetOpen(fmt.Sprintf("%s@%s", datasetPath, snapshotName))
+	if err != nil {
+		return snapshot, fmt.Errorf("%w: %s@%s", SnapshotNotFoundError, datasetPath, snapshotName)
+	}
+	return snapshot, nil
+}
+
+func (m *ZFSManager) HoldSnapshot(datasetPath string, snapshotName string, tag string) error {
+	if len(tag) == 0 {
+		return errors.New("hold tag is required")
+	}
+	snapshot, err := m.openSnapshot(datasetPath, snapshotName)
+	if err != nil {
+		return err
+	}
+	defer snapshot.Close()
+	return snapshot.Hold(tag)
+}
+
+func (m *ZFSManager) ReleaseSnapshot(datasetPath string, snapshotName string, tag string) error {
+	snapshot, err := m.openSnapshot(datasetPath, snapshotName)
+	if err != nil {
+		return err
+	}
+	defer snapshot.Close()
+	return snapshot.Release(tag)
+}
+
+// GetSnapshotHolds return holds on snapshot
+func GetSnapshotHolds(snapshot *libzfs.Dataset) []SnapshotHold {
+	tags, err := snapshot.Holds()
+	if err != nil {
+		return nil
+	}
+	holds := make([]SnapshotHold, 0, len(tags))
+	for _, tag := range tags {
+		holds = append(holds, SnapshotHold{Tag: tag.Name, Created: tag.Timestamp})
+	}
+	return holds
+}
+
+type Bookmark struct {
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	GUID      string    `json:"guid"`
+	CreateTxg uint64    `json:"createTxg"`
+	Created   time.Time `json:"created"`
+}
+
+func (m *ZFSManager) CreateBookmark(datasetPath string, snapshotName string, bookmarkName string) error {
+	snapshot, err := m.openSnapshot(datasetPath, snapshotName)
+	if err != nil {
+		return err
+	}
+	snapshot.Close()
+	if len(bookmarkName) == 0 {
+		bookmarkName = snapshotName
+	}
+	_, err = runZfs("bookmark", fmt.Sprintf("%s@%s", datasetPath, snapshotName), fmt.Sprintf("%s#%s", datasetPath, bookmarkName))
+	return err
+}
+
+func (m *ZFSManager) GetBookmarks(datasetPath string) ([]Bookmark, error) {
+	out, err := runZfs("list", "-Hp", "-t", "bookmark", "-d", "1", "-o", "name,guid,createtxg,creation", datasetPath)
+	if err != nil {
+		return nil, err
+	}
+	bookmarks := make([]Bookmark, 0)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 4 {
+			continue
+		}
+		bookmark := Bookmark{
+			Path: fields[0],
+			GUID: fields[1],
+		}
+		if idx := strings.Index(fields[0], "#"); idx >= 0 {
+			bookmark.Name = fields[0][idx+1:]
+		}
+		bookmark.CreateTxg, _ = strconv.ParseUint(fields[2], 10, 64)
+		if creation, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+			bookmark.Created = time.Unix(creation, 0)
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+	return bookmarks, nil
+}
+
+func (m *ZFSManager) DeleteBookmark(datasetPath string, bookmarkName string) error {
+	if len(bookmarkName) == 0 || strings.ContainsAny(bookmarkName, "/@#") {
+		return fmt.Errorf("invalid bookmark name %s", bookmarkName)
+	}
+	_, err := runZfs("destroy", fmt.Sprintf("%s#%s", datasetPath, bookmarkName))
+	return err
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
+)
+
+type ImportablePool struct {
+	Name    string
+	GUID    string
+	Comment string
+	State   string
+	Status  string
+	VDevs   libzfs.VDevTree
+}
+
+// GetImportablePools search attached disks for exported or foreign pools
+func (m *ZFSManager) GetImportablePools(searchPaths []string) ([]ImportablePool, error) {
+	exportedPools, err := libzfs.PoolImportSearch(searchPaths)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]ImportablePool, 0, len(exportedPools))
+	for _, exportedPool := range exportedPools {
+		result = append(result, ImportablePool{
+			Name:    exportedPool.Name,
+			GUID:    strconv.FormatUint(exportedPool.GUID, 10),
+			Comment: exportedPool.Comment,
+			State:   exportedPool.State.String(),
+			Status:  exportedPool.Status.String(),
+			VDevs:   exportedPool.VDevs,
+		})
+	}
+	return result, nil
+}
+
+type ImportPoolOption struct {
+	// Name or GUID of pool to import
+	Pool        string   `json:"pool"`
+	NewName     string   `json:"newName"`
+	Readonly    bool     `json:"readonly"`
+	Force       bool     `json:"force"`
+	SearchPaths []string `json:"searchPaths"`
+}
+
+// ImportPool import pool and register it as storage
+func (m *ZFSManager) ImportPool(option ImportPoolOption) error {
+	if len(option.Pool) == 0 {
+		return PoolNotFoundError
+	}
+	args := []string{"import"}
+	if option.Force {
+		args = append(args, "-f")
+	}
+	if option.Readonly {
+		args = append(args, "-o", "readonly=on")
+	}
+	for _, searchPath := range option.SearchPaths {
+		args = append(args, "-d", searchPath)
+	}
+	args = append(args, option.Pool)
+	if len(option.NewName) > 0 {
+		args = append(args, option.NewName)
+	}
+	_, err := runZpool(args...)
+	if err != nil {
+		return err
+	}
+	_, _, err = SyncZFSMountsToStorage()
+	return err
+}
+
+// ExportPool unmount all datasets of pool and export it
+func (m *ZFSManager) ExportPool(name string, force bool) error {
+	pool, err := m.openPool(name)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	dataset, err := libzfs.DatasetOpen(name)
+	if err != nil {
+		return err
+	}
+	err = dataset.UnmountAll(0)
+	dataset.Close()
+	if err != nil && !force {
+		return err
+	}
+	err = pool.Export(force, fmt.Sprintf("export pool %s", name))
+	if err != nil {
+		return err
+	}
+	DefaultStoragePool.UnloadZFSPoolStorages(name)
+	return nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var IOStatLogger = logrus.New().WithFields(logrus.Fields{
+	"scope": "IOStat",
+})
+
+type IOStatSample struct {
+	Time       int64  `json:"time"`
+	ReadOps    uint64 `json:"readOps"`
+	WriteOps   uint64 `json:"writeOps"`
+	ReadBytes  uint64 `json:"readBytes"`
+	WriteBytes uint64 `json:"writeBytes"`
+	// ReadLatency and WriteLatency are average total wait in nanoseconds
+	ReadLatency  uint64 `json:"readLatency"`
+	WriteLatency uint64 `json:"writeLatency"`
+}
+
+// IOStatResolution is sample interval and number of samples kept
+type IOStatResolution struct {
+	Name     string
+	Interval int64
+	Size     int
+}
+
+var IOStatResolutions = []IOStatResolution{
+	{Name: "second", Interval: 1, Size: 600},
+	{Name: "minute", Interval: 60, Size: 1440},
+	{Name: "hour", Interval: 3600, Size: 720},
+}
+
+// ioStatSeries keep bounded history of a pool or vdev in every resolution
+type ioStatSeries struct {
+	History map[string][]IOStatSample
+	// pending is samples of current bucket not yet averaged
+	pending map[string][]IOStatSample
+}
+
+func newIOStatSeries() *ioStatSeries {
+	return &ioStatSeries{
+		History: map[string][]IOStatSample{},
+		pending: map[string][]IOStatSample{},
+	}
+}
+
+func averageIOStatSamples(bucketTime int64, samples []IOStatSample) IOStatSample {
+	result := IOStatSample{Time: bucketTime}
+	count := uint64(len(samples))
+	for _, sample := range samples {
+		result.ReadOps += sample.ReadOps
+		result.WriteOps += sample.WriteOps
+		result.ReadBytes += sample.ReadBytes
+		result.WriteBytes += sample.WriteBytes
+		result.ReadLatency += sample.ReadLatency
+		result.WriteLatency += sample.WriteLatency
+	}
+	result.ReadOps /= count
+	result.WriteOps /= count
+	result.ReadBytes /= count
+	result.WriteBytes /= count
+	result.ReadLatency /= count
+	result.WriteLatency /= count
+	return result
+}
+
+func (s *ioStatSeries) add(sample IOStatSample) {
+	for _, resolution := range IOStatResolutions {
+		pending := s.pending[resolution.Name]
+		if len(pending) > 0 && pending[0].Time/resolution.Interval != sample.Time/resolution.Interval {
+			bucketTime := pending[0].Time / resolution.Interval * resolution.Interval
+			history := append(s.History[resolution.Name], averageIOStatSamples(bucketTime, pending))
+			if len(history) > resolution.Size {
+				history = history[len(history)-resolution.Size:]
+			}
+			s.History[resolution.Name] = history
+			pending = pending[:0]
+		}
+		s.pending[resolution.Name] = append(pending, sample)
+	}
+}
+
+type PoolIOStatMonitor struct {
+	// Series key is pool name or pool/vdev
+	Series map[string]*ioStatSeries
+	pools  map[string]bool
+	sync.RWMutex
+}
+
+var DefaultPoolIOStatMonitor = PoolIOStatMonitor{
+	Series: map[string]*ioStatSeries{},
+}
+
+func (m *PoolIOStatMonitor) Run() {
+	go func() {
+		for {
+			if err := m.sample(); err != nil {
+				IOStatLogger.Error(err)
+			}
+			<-time.After(30 * time.Second)
+		}
+	}()
+}
+
+func (m *PoolIOStatMonitor) refreshPools() {
+	pools, err := DefaultZFSManager.GetPoolsHealth()
+	if err != nil {
+		return
+	}
+	m.pools = map[string]bool{}
+	for _, pool := range pools {
+		m.pools[pool.Name] = true
+	}
+}
+
+func parseIOStatNumber(value string) uint64 {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return uint64(number)
+}
+
+// sample follow zpool iostat output, each report start with unix timestamp line
+func (m *PoolIOStatMonitor) sample() error {
+	m.refreshPools()
+	if len(m.pools) == 0 {
+		return nil
+	}
+	cmd := exec.Command("zpool", "iostat", "-Hpvly", "-T", "u", "1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(stdout)
+	var reportTime int64
+	reports := 0
+	currentPool := ""
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		if timestamp, err := strconv.ParseInt(line, 10, 64); err == nil {
+			reportTime = timestamp
+			currentPool = ""
+			reports += 1
+			// pools may be created or imported while following
+			if reports%60 == 0 {
+				m.refreshPools()
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 7 || fields[3] == "-" {
+			continue
+		}
+		name := fields[0]
+		key := name
+		if m.pools[name] {
+			currentPool = name
+		} else if len(currentPool) > 0 {
+			key = fmt.Sprintf("%s/%s", currentPool, name)
+		} else {
+			continue
+		}
+		sample := IOStatSample{
+			Time:       reportTime,
+			ReadOps:    parseIOStatNumber(fields[3]),
+			WriteOps:   parseIOStatNumber(fields[4]),
+			ReadBytes:  parseIOStatNumber(fields[5]),
+			WriteBytes: parseIOStatNumber(fields[6]),
+		}
+		if len(fields) >= 9 {
+			sample.ReadLatency = parseIOStatNumber(fields[7])
+			sample.WriteLatency = parseIOStatNumber(fields[8])
+		}
+		m.Lock()
+		series, ok := m.Series[key]
+		if !ok {
+			series = newIOStatSeries()
+			m.Series[key] = series
+		}
+		series.add(sample)
+		m.Unlock()
+	}
+	return cmd.Wait()
+}
+
+type IOStatSeries struct {
+	Name    string         `json:"name"`
+	Samples []IOStatSample `json:"samples"`
+}
+
+// GetPoolIOStat return history of pool and its vdevs in resolution
+func (m *PoolIOStatMonitor) GetPoolIOStat(poolName string, resolution string) ([]IOStatSeries, error) {
+	found := false
+	for _, item := range IOStatResolutions {
+		if item.Name == resolution {
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown resolution %s", resolution)
+	}
+	m.RLock()
+	defer m.RUnlock()
+	result := make([]IOStatSeries, 0)
+	for key, series := range m.Series {
+		if len(poolName) > 0 && key != poolName && !strings.HasPrefix(key, poolName+"/") {
+			continue
+		}
+		samples := append([]IOStatSample{}, series.History[resolution]...)
+		result = append(result, IOStatSeries{Name: key, Samples: samples})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
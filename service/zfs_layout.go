@@ -0,0 +1,267 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/projectxpolaris/youplus/utils"
+)
+
+var InvalidPoolLayoutError = errors.New("invalid pool layout")
+
+type VdevPreview struct {
+	Class          string   `json:"class"`
+	Type           string   `json:"type"`
+	Disks          []string `json:"disks"`
+	Capacity       uint64   `json:"capacity"`
+	FaultTolerance int      `json:"faultTolerance"`
+}
+
+type PoolLayoutPreview struct {
+	Valid    bool     `json:"valid"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+	// RawCapacity is total size of data disks, UsableCapacity is estimated size after redundancy
+	RawCapacity    uint64        `json:"rawCapacity"`
+	UsableCapacity uint64        `json:"usableCapacity"`
+	FaultTolerance int           `json:"faultTolerance"`
+	Vdevs          []VdevPreview `json:"vdevs"`
+}
+
+type layoutDevice struct {
+	Name       string
+	Size       uint64
+	Rotational bool
+}
+
+type layoutChecker struct {
+	preview *PoolLayoutPreview
+	blocks  map[string]map[string]string
+	used    map[string]bool
+}
+
+func (c *layoutChecker) errorf(format string, args ...interface{}) {
+	c.preview.Errors = append(c.preview.Errors, fmt.Sprintf(format, args...))
+}
+
+func (c *layoutChecker) warnf(format string, args ...interface{}) {
+	c.preview.Warnings = append(c.preview.Warnings, fmt.Sprintf(format, args...))
+}
+
+// device resolve node path to attached block device and check it can be used
+func (c *layoutChecker) device(path string) (*layoutDevice, bool) {
+	name := filepath.Base(path)
+	if realPath, err := filepath.EvalSymlinks(diskDevicePath(path)); err == nil {
+		name = filepath.Base(realPath)
+	}
+	if c.used[name] {
+		c.errorf("%s is used more than once", name)
+		return nil, false
+	}
+	c.used[name] = true
+	block, ok := c.blocks[name]
+	if !ok {
+		c.errorf("%s: %s", name, DiskNotFoundError.Error())
+		return nil, false
+	}
+	if block["type"] == "disk" {
+		if err := CheckDiskAvailable(name); err != nil {
+			c.errorf("%s", err.Error())
+			return nil, false
+		}
+	} else if len(block["mountpoint"]) > 0 {
+		c.errorf("%s: %s is mounted on %s", name, DiskInUseError.Error(), block["mountpoint"])
+		return nil, false
+	}
+	size, _ := strconv.ParseUint(block["size"], 10, 64)
+	return &layoutDevice{Name: name, Size: size, Rotational: block["rota"] == "1"}, true
+}
+
+// vdev check one top-level vdev, return false when it is invalid
+func (c *layoutChecker) vdev(class string, node Node) (VdevPreview, bool) {
+	preview := VdevPreview{Class: class, Type: node.Type, Disks: []string{}}
+	if len(preview.Type) == 0 {
+		preview.Type = "disk"
+	}
+	members := []Node{node}
+	if preview.Type != "disk" {
+		if _, ok := VdevTypeMapping[preview.Type]; !ok {
+			c.errorf("unsupported vdev type %s", node.Type)
+			return preview, false
+		}
+		members = node.Devices
+	}
+	devices := make([]*layoutDevice, 0, len(members))
+	valid := true
+	for _, member := range members {
+		// zpool vdev is one level deep, nested groups can not be created
+		if len(member.Devices) > 0 || (preview.Type != "disk" && len(member.Type) > 0 && member.Type != "disk") {
+			c.errorf("%s vdev can not contain nested vdev", preview.Type)
+			valid = false
+			continue
+		}
+		device, ok := c.device(member.Path)
+		if !ok {
+			valid = false
+			continue
+		}
+		devices = append(devices, device)
+		preview.Disks = append(preview.Disks, device.Name)
+	}
+	if !valid {
+		return preview, false
+	}
+	if len(devices) == 0 {
+		c.errorf("%s vdev has no device", preview.Type)
+		return preview, false
+	}
+	minSize, maxSize := devices[0].Size, devices[0].Size
+	rotational, solid := false, false
+	for _, device := range devices {
+		if device.Size < minSize {
+			minSize = device.Size
+		}
+		if device.Size > maxSize {
+			maxSize = device.Size
+		}
+		if device.Rotational {
+			rotational = true
+		} else {
+			solid = true
+		}
+	}
+	if minSize != maxSize {
+		c.warnf("%s vdev (%s) mixes disk sizes, capacity is limited by the smallest disk", preview.Type, strings.Join(preview.Disks, ","))
+	}
+	if rotational && solid {
+		c.warnf("%s vdev (%s) mixes rotational and SSD devices", preview.Type, strings.Join(preview.Disks, ","))
+	}
+	count := len(devices)
+	parity := VdevParityMapping[preview.Type]
+	switch {
+	case preview.Type == "disk":
+		preview.Capacity = minSize
+	case preview.Type == "mirror":
+		if count < 2 {
+			c.errorf("mirror vdev requires at least 2 devices")
+			return preview, false
+		}
+		preview.Capacity = minSize
+		preview.FaultTolerance = count - 1
+	case strings.HasPrefix(preview.Type, "raidz"):
+		if count < parity+2 {
+			c.errorf("%s vdev requires at least %d devices", preview.Type, parity+2)
+			return preview, false
+		}
+		preview.Capacity = uint64(count-parity) * minSize
+		preview.FaultTolerance = parity
+	case strings.HasPrefix(preview.Type, "draid"):
+		data := node.Data
+		if data <= 0 {
+			data = count - node.DistSpares - parity
+			if data > 8 {
+				data = 8
+			}
+		}
+		if data < 1 || count < data+parity+node.DistSpares {
+			c.errorf("%s vdev with %d data and %d spares requires at least %d devices", preview.Type, data, node.DistSpares, data+parity+node.DistSpares)
+			return preview, false
+		}
+		preview.Capacity = uint64(count-node.DistSpares) * minSize / uint64(data+parity) * uint64(data)
+		preview.FaultTolerance = parity
+	}
+	return preview, true
+}
+
+// PreviewPoolLayout validate node tree against attached disks and estimate capacity without creating pool
+func PreviewPoolLayout(node Node) *PoolLayoutPreview {
+	preview := &PoolLayoutPreview{
+		Errors:   []string{},
+		Warnings: []string{},
+		Vdevs:    []VdevPreview{},
+	}
+	checker := &layoutChecker{
+		preview: preview,
+		blocks:  map[string]map[string]string{},
+		used:    map[string]bool{},
+	}
+	for _, block := range utils.Lsblk() {
+		checker.blocks[block["name"]] = block
+	}
+	if len(node.Devices) == 0 {
+		checker.errorf("pool requires at least one data vdev")
+	}
+	dataTypes := map[string]bool{}
+	minDataDisk := uint64(0)
+	for idx, dev := range node.Devices {
+		vdev, ok := checker.vdev("data", dev)
+		if !ok {
+			continue
+		}
+		preview.Vdevs = append(preview.Vdevs, vdev)
+		dataTypes[strings.TrimSuffix(vdev.Type, "1")] = true
+		preview.UsableCapacity += vdev.Capacity
+		if idx == 0 || vdev.FaultTolerance < preview.FaultTolerance {
+			preview.FaultTolerance = vdev.FaultTolerance
+		}
+		for _, name := range vdev.Disks {
+			size, _ := strconv.ParseUint(checker.blocks[name]["size"], 10, 64)
+			preview.RawCapacity += size
+			if minDataDisk == 0 || size < minDataDisk {
+				minDataDisk = size
+			}
+		}
+	}
+	if len(dataTypes) > 1 {
+		checker.warnf("data vdevs use different types, redundancy of pool is limited by the weakest vdev")
+	}
+	if len(preview.Vdevs) > 0 && preview.FaultTolerance == 0 {
+		checker.warnf("pool has no redundancy, failure of any data disk loses the pool")
+	}
+	for _, class := range []struct {
+		Name  string
+		Nodes []Node
+	}{
+		{Name: "special", Nodes: node.Special},
+		{Name: "dedup", Nodes: node.Dedup},
+		{Name: "log", Nodes: node.Logs},
+	} {
+		for _, dev := range class.Nodes {
+			vdev, ok := checker.vdev(class.Name, dev)
+			if !ok {
+				continue
+			}
+			preview.Vdevs = append(preview.Vdevs, vdev)
+			if class.Name != "log" && vdev.FaultTolerance < preview.FaultTolerance {
+				checker.warnf("%s vdev has less redundancy than data vdevs, losing it loses the pool", class.Name)
+			}
+		}
+	}
+	for _, class := range []struct {
+		Name  string
+		Nodes []Node
+	}{
+		{Name: "cache", Nodes: node.L2},
+		{Name: "spare", Nodes: node.Spares},
+	} {
+		for _, dev := range class.Nodes {
+			if len(dev.Type) > 0 && dev.Type != "disk" {
+				checker.errorf("%s device must be single disk", class.Name)
+				continue
+			}
+			vdev, ok := checker.vdev(class.Name, dev)
+			if !ok {
+				continue
+			}
+			preview.Vdevs = append(preview.Vdevs, vdev)
+			if class.Name == "spare" && vdev.Capacity < minDataDisk {
+				checker.warnf("spare %s is smaller than data disks and can not replace them", strings.Join(vdev.Disks, ","))
+			}
+		}
+	}
+	preview.Valid = len(preview.Errors) == 0
+	return preview
+}
@@ -32,3 +32,25 @@ func (m *ZFSManager) GetDatasetByPath(path string) (*libzfs.Dataset, error) {
 	}
 	return &dataset, nil
 }
+
+// GetDatasetPathByMountPath find the deepest dataset which path is mounted under
+func (m *ZFSManager) GetDatasetPathByMountPath(path string) (string, string, error) {
+	datasets, err := m.GetAllDataset(DatasetQueryFilter{Type: "filesystem"})
+	if err != nil {
+		return "", "", err
+	}
+	defer m.CloseAllDataset(datasets)
+	datasetPath := ""
+	mountPoint := ""
+	for _, dataset := range datasets {
+		value := dataset.Properties[libzfs.DatasetPropMountpoint].Value
+		if len(value) == 0 || !strings.HasPrefix(value, "/") {
+			continue
+		}
+		if (path == value || strings.HasPrefix(path, strings.TrimSuffix(value, "/")+"/")) && len(value) > len(mountPoint) {
+			mountPoint = value
+			datasetPath, _ = dataset.Path()
+		}
+	}
+	return datasetPath, mountPoint, nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
+)
+
+var (
+	UnsupportedPropertyError  = errors.New("unsupported dataset property")
+	InvalidPropertyValueError = errors.New("invalid dataset property value")
+)
+
+type DatasetPropertyDefinition struct {
+	Prop libzfs.Prop
+	// Values is allowed values, empty means use Validate
+	Values []string
+	// Validate check value when Values is empty
+	Validate func(value string) error
+	// CreateOnly property can only be set at creation time
+	CreateOnly bool
+	// ResetValue is set instead of inherit for property can not be inherited
+	ResetValue string
+}
+
+var onOffValues = []string{"on", "off"}
+
+var DatasetPropertyDefinitions = map[string]DatasetPropertyDefinition{
+	"compression": {
+		Prop: libzfs.DatasetPropCompression,
+		Values: []string{
+			"on", "off", "lzjb", "zle", "lz4", "zstd", "zstd-fast",
+			"gzip", "gzip-1", "gzip-2", "gzip-3", "gzip-4", "gzip-5", "gzip-6", "gzip-7", "gzip-8", "gzip-9",
+		},
+	},
+	"recordsize": {
+		Prop:     libzfs.DatasetPropRecordsize,
+		Validate: validateRecordSize,
+	},
+	"atime": {
+		Prop:   libzfs.DatasetPropAtime,
+		Values: onOffValues,
+	},
+	"quota": {
+		Prop:       libzfs.DatasetPropQuota,
+		Validate:   validateSizeOrNone,
+		ResetValue: "none",
+	},
+	"refquota": {
+		Prop:       libzfs.DatasetPropRefquota,
+		Validate:   validateSizeOrNone,
+		ResetValue: "none",
+	},
+	"reservation": {
+		Prop:       libzfs.DatasetPropReservation,
+		Validate:   validateSizeOrNone,
+		ResetValue: "none",
+	},
+	"sync": {
+		Prop:   libzfs.DatasetPropSync,
+		Values: []string{"standard", "always", "disabled"},
+	},
+	"xattr": {
+		Prop:   libzfs.DatasetPropXattr,
+		Values: []string{"on", "off", "sa", "dir"},
+	},
+	"acltype": {
+		Prop:   libzfs.DatasetPropAcltype,
+		Values: []string{"off", "noacl", "posixacl", "posix", "nfsv4"},
+	},
+	"dedup": {
+		Prop:   libzfs.DatasetPropDedup,
+		Values: []string{"on", "off", "verify", "sha256", "sha256,verify", "sha512", "sha512,verify", "skein", "skein,verify", "edonr,verify"},
+	},
+	"copies": {
+		Prop:   libzfs.DatasetPropCopies,
+		Values: []string{"1", "2", "3"},
+	},
+	"casesensitivity": {
+		Prop:       libzfs.DatasetPropCase,
+		Values:     []string{"sensitive", "insensitive", "mixed"},
+		CreateOnly: true,
+	},
+}
+
+var sizePattern = regexp.MustCompile(`^(\d+(\.\d+)?)([KMGTPE]?)(I?B)?$`)
+
+var sizeUnits = map[string]float64{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+	"P": 1 << 50,
+	"E": 1 << 60,
+}
+
+// ParseZFSSize parse size like 128K, 10G into bytes
+func ParseZFSSize(value string) (uint64, error) {
+	match := sizePattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(value)))
+	if match == nil {
+		return 0, fmt.Errorf("invalid size %s", value)
+	}
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(number * sizeUnits[match[3]]), nil
+}
+
+func validateSizeOrNone(value string) error {
+	if value == "none" {
+		return nil
+	}
+	_, err := ParseZFSSize(value)
+	return err
+}
+
+func validateRecordSize(value string) error {
+	size, err := ParseZFSSize(value)
+	if err != nil {
+		return err
+	}
+	if size < 512 || size > 16<<20 || size&(size-1) != 0 {
+		return fmt.Errorf("recordsize must be power of 2 from 512 to 16M")
+	}
+	return nil
+}
+
+// ValidateDatasetProperty check property is supported and value is valid
+func ValidateDatasetProperty(name string, value string) (*DatasetPropertyDefinition, error) {
+	definition, ok := DatasetPropertyDefinitions[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", UnsupportedPropertyError, name)
+	}
+	if len(definition.Values) > 0 {
+		for _, allowed := range definition.Values {
+			if value == allowed {
+				return &definition, nil
+			}
+		}
+		return nil, fmt.Errorf("%w %s for %s, allowed: %s", InvalidPropertyValueError, value, name, strings.Join(definition.Values, ","))
+	}
+	if definition.Validate != nil {
+		if err := definition.Validate(value); err != nil {
+			return nil, fmt.Errorf("%w %s for %s: %s", InvalidPropertyValueError, value, name, err.Error())
+		}
+	}
+	return &definition, nil
+}
+
+// ToDatasetCreateProperties validate properties and convert to libzfs properties for dataset creation
+func ToDatasetCreateProperties(props map[string]string) (map[libzfs.Prop]libzfs.Property, error) {
+	result := make(map[libzfs.Prop]libzfs.Property)
+	for name, value := range props {
+		definition, err := ValidateDatasetProperty(name, value)
+		if err != nil {
+			return nil, err
+		}
+		result[definition.Prop] = libzfs.Property{Value: value}
+	}
+	return result, nil
+}
+
+type DatasetProperty struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Source    string `json:"source"`
+	Inherited bool   `json:"inherited"`
+}
+
+func (m *ZFSManager) GetDatasetProperties(datasetPath string) ([]DatasetProperty, error) {
+	dataset, err := libzfs.DatasetOpenSingle(datasetPath)
+	if err != nil {
+		return nil, err
+	}
+	defer dataset.Close()
+	names := make([]string, 0, len(DatasetPropertyDefinitions))
+	for name := range DatasetPropertyDefinitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]DatasetProperty, 0)
+	for _, name := range names {
+		definition := DatasetPropertyDefinitions[name]
+		prop, err := dataset.GetProperty(definition.Prop)
+		if err != nil {
+			return nil, fmt.Errorf("read property %s: %w", name, err)
+		}
+		result = append(result, DatasetProperty{
+			Name:      name,
+			Value:     prop.Value,
+			Source:    prop.Source,
+			Inherited: strings.HasPrefix(prop.Source, "inherited"),
+		})
+	}
+	return result, nil
+}
+
+// SetDatasetProperties set dataset properties, properties with empty value are reset to inherit
+func (m *ZFSManager) SetDatasetProperties(datasetPath string, props map[string]string) error {
+	dataset, err := libzfs.DatasetOpenSingle(datasetPath)
+	if err != nil {
+		return err
+	}
+	defer dataset.Close()
+	for name, value := range props {
+		if len(value) == 0 {
+			if err = m.InheritDatasetProperty(datasetPath, name); err != nil {
+				return err
+			}
+			continue
+		}
+		definition, err := ValidateDatasetProperty(name, value)
+		if err != nil {
+			return err
+		}
+		if definition.CreateOnly {
+			return fmt.Errorf("%s can only be set when creating dataset", name)
+		}
+		if err = dataset.SetProperty(definition.Prop, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InheritDatasetProperty reset property to the value inherited from parent
+func (m *ZFSManager) InheritDatasetProperty(datasetPath string, name string) error {
+	definition, ok := DatasetPropertyDefinitions[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", UnsupportedPropertyError, name)
+	}
+	if definition.CreateOnly {
+		return fmt.Errorf("%s can only be set when creating dataset", name)
+	}
+	if len(definition.ResetValue) > 0 {
+		dataset, err := libzfs.DatasetOpenSingle(datasetPath)
+		if err != nil {
+			return err
+		}
+		defer dataset.Close()
+		return dataset.SetProperty(definition.Prop, definition.ResetValue)
+	}
+	_, err := runZfs("inherit", name, datasetPath)
+	return err
+}
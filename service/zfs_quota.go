@@ -0,0 +1,149 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	UnsupportedQuotaTypeError = errors.New("unsupported quota type")
+	QuotaTargetNotFoundError  = errors.New("quota target not found")
+)
+
+// QuotaTypeMapping is quota type to zfs space command and property prefix
+var QuotaTypeMapping = map[string]struct {
+	Command  string
+	Property string
+}{
+	"user":       {Command: "userspace", Property: "userquota"},
+	"userobj":    {Command: "userspace", Property: "userobjquota"},
+	"group":      {Command: "groupspace", Property: "groupquota"},
+	"groupobj":   {Command: "groupspace", Property: "groupobjquota"},
+	"project":    {Command: "projectspace", Property: "projectquota"},
+	"projectobj": {Command: "projectspace", Property: "projectobjquota"},
+}
+
+type QuotaUsage struct {
+	Name     string `json:"name"`
+	Used     uint64 `json:"used"`
+	Quota    uint64 `json:"quota"`
+	ObjUsed  uint64 `json:"objUsed"`
+	ObjQuota uint64 `json:"objQuota"`
+}
+
+func checkQuotaTarget(quotaType string, name string) error {
+	switch strings.TrimSuffix(quotaType, "obj") {
+	case "user":
+		if DefaultUserManager.GetUserByName(name) == nil {
+			return fmt.Errorf("%w: user %s", QuotaTargetNotFoundError, name)
+		}
+	case "group":
+		if DefaultUserManager.GetGroupByName(name) == nil {
+			return fmt.Errorf("%w: group %s", QuotaTargetNotFoundError, name)
+		}
+	case "project":
+		if _, err := strconv.ParseUint(name, 10, 32); err != nil {
+			return fmt.Errorf("%w: project id must be number", QuotaTargetNotFoundError)
+		}
+	}
+	return nil
+}
+
+// SetQuota set quota of user, group or project on dataset, size none remove the quota
+func (m *ZFSManager) SetQuota(datasetPath string, quotaType string, name string, size string) error {
+	mapping, ok := QuotaTypeMapping[quotaType]
+	if !ok {
+		return fmt.Errorf("%w: %s", UnsupportedQuotaTypeError, quotaType)
+	}
+	if err := checkQuotaTarget(quotaType, name); err != nil {
+		return err
+	}
+	if strings.HasSuffix(quotaType, "obj") {
+		if _, err := strconv.ParseUint(size, 10, 64); err != nil && size != "none" {
+			return fmt.Errorf("invalid object count %s", size)
+		}
+	} else if err := validateSizeOrNone(size); err != nil {
+		return err
+	}
+	_, err := runZfs("set", fmt.Sprintf("%s@%s=%s", mapping.Property, name, size), datasetPath)
+	return err
+}
+
+func parseQuotaNumber(value string) uint64 {
+	number, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// GetQuotaUsages return space used and quota of each user, group or project on dataset
+func (m *ZFSManager) GetQuotaUsages(datasetPath string, quotaType string) ([]QuotaUsage, error) {
+	mapping, ok := QuotaTypeMapping[quotaType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", UnsupportedQuotaTypeError, quotaType)
+	}
+	out, err := runZfs(mapping.Command, "-Hp", "-o", "name,used,quota,objused,objquota", datasetPath)
+	if err != nil {
+		return nil, err
+	}
+	usages := make([]QuotaUsage, 0)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 5 {
+			continue
+		}
+		usages = append(usages, QuotaUsage{
+			Name:     fields[0],
+			Used:     parseQuotaNumber(fields[1]),
+			Quota:    parseQuotaNumber(fields[2]),
+			ObjUsed:  parseQuotaNumber(fields[3]),
+			ObjQuota: parseQuotaNumber(fields[4]),
+		})
+	}
+	return usages, nil
+}
+
+type ShareQuota struct {
+	Used      uint64 `json:"used"`
+	Quota     uint64 `json:"quota"`
+	Remaining uint64 `json:"remaining"`
+}
+
+// GetUserShareQuota return space user can still write to share folder on zfs dataset,
+// remaining is limited by user quota and space available on dataset
+func GetUserShareQuota(path string, username string) *ShareQuota {
+	datasetPath, _, err := DefaultZFSManager.GetDatasetPathByMountPath(path)
+	if err != nil || len(datasetPath) == 0 {
+		return nil
+	}
+	out, err := runZfs(
+		"get", "-Hp", "-o", "value",
+		fmt.Sprintf("userused@%s,userquota@%s,available", username, username),
+		datasetPath,
+	)
+	if err != nil {
+		return nil
+	}
+	values := strings.Split(strings.TrimSpace(out), "\n")
+	if len(values) < 3 {
+		return nil
+	}
+	quota := &ShareQuota{
+		Used:      parseQuotaNumber(values[0]),
+		Quota:     parseQuotaNumber(values[1]),
+		Remaining: parseQuotaNumber(values[2]),
+	}
+	if quota.Quota > 0 {
+		left := uint64(0)
+		if quota.Quota > quota.Used {
+			left = quota.Quota - quota.Used
+		}
+		if left < quota.Remaining {
+			quota.Remaining = left
+		}
+	}
+	return quota
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	NotZFSPathError           = errors.New("path is not on zfs dataset")
+	InvalidSnapshotPathError  = errors.New("path is outside of snapshot")
+	SnapshotNotFoundError     = errors.New("snapshot not found")
+	InvalidRestoreTargetError = errors.New("restore target must be directory in storage or share folder")
+)
+
+type SnapshotFileItem struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	IsDir   bool      `json:"isDir"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// SnapshotLocation is a path on dataset and where its content is in snapshot
+type SnapshotLocation struct {
+	Dataset    string
+	MountPoint string
+	Snapshot   string
+	// Root is the directory of path inside .zfs/snapshot
+	Root string
+	// Path is the live path
+	Path string
+}
+
+// Resolve return path inside snapshot for relative path, path escaping root directly or by symlink is rejected
+func (l *SnapshotLocation) Resolve(relativePath string) (string, error) {
+	target := filepath.Join(l.Root, filepath.Clean("/"+relativePath))
+	if !isPathUnder(target, l.Root) {
+		return "", InvalidSnapshotPathError
+	}
+	// snapshot is read-only, symlinks resolved here can not change before they are used
+	realRoot, err := filepath.EvalSymlinks(l.Root)
+	if err != nil {
+		return "", err
+	}
+	realTarget, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		return "", err
+	}
+	if !isPathUnder(realTarget, realRoot) {
+		return "", InvalidSnapshotPathError
+	}
+	return target, nil
+}
+
+func isPathUnder(path string, root string) bool {
+	return path == root || strings.HasPrefix(path, strings.TrimSuffix(root, "/")+"/")
+}
+
+// LocateSnapshotPath map real path like share folder path to the same path in dataset snapshot
+func (m *ZFSManager) LocateSnapshotPath(path string, snapshot string) (*SnapshotLocation, error) {
+	path = filepath.Clean(path)
+	datasetPath, mountPoint, err := m.GetDatasetPathByMountPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(datasetPath) == 0 {
+		return nil, NotZFSPathError
+	}
+	if len(snapshot) == 0 || strings.ContainsAny(snapshot, "/@") {
+		return nil, SnapshotNotFoundError
+	}
+	snapshotRoot := filepath.Join(mountPoint, ".zfs", "snapshot", snapshot)
+	if _, err = os.Stat(snapshotRoot); err != nil {
+		return nil, fmt.Errorf("%w: %s@%s", SnapshotNotFoundError, datasetPath, snapshot)
+	}
+	relativePath, err := filepath.Rel(mountPoint, path)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapshotLocation{
+		Dataset:    datasetPath,
+		MountPoint: mountPoint,
+		Snapshot:   snapshot,
+		Root:       filepath.Join(snapshotRoot, relativePath),
+		Path:       path,
+	}, nil
+}
+
+// ReadSnapshotDir list directory in snapshot, dir is relative to path
+func (m *ZFSManager) ReadSnapshotDir(path string, snapshot string, dir string) ([]SnapshotFileItem, error) {
+	location, err := m.LocateSnapshotPath(path, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	target, err := location.Resolve(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(target)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]SnapshotFileItem, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		relativePath, _ := filepath.Rel(location.Root, filepath.Join(target, entry.Name()))
+		items = append(items, SnapshotFileItem{
+			Name:    entry.Name(),
+			Path:    relativePath,
+			IsDir:   entry.IsDir(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+	return items, nil
+}
+
+type RestoreSnapshotFilesOption struct {
+	Path     string   `json:"path"`
+	Snapshot string   `json:"snapshot"`
+	Files    []string `json:"files"`
+	// Target is directory to restore into, empty means original location
+	Target    string `json:"target"`
+	Overwrite bool   `json:"overwrite"`
+}
+
+type RestoreSnapshotFilesExtra struct {
+	Dataset  string `json:"dataset"`
+	Snapshot string `json:"snapshot"`
+	Target   string `json:"target"`
+	Current  string `json:"current"`
+	Restored int    `json:"restored"`
+	Skipped  int    `json:"skipped"`
+}
+
+type RestoreSnapshotFilesCallback struct {
+	OnDone  func(task *RestoreSnapshotFilesTask)
+	OnError func(task *RestoreSnapshotFilesTask)
+}
+
+type RestoreSnapshotFilesTask struct {
+	BaseTask
+	Extra    RestoreSnapshotFilesExtra
+	Callback RestoreSnapshotFilesCallback
+	option   RestoreSnapshotFilesOption
+	location *SnapshotLocation
+}
+
+func (t *RestoreSnapshotFilesTask) OnError(err error) {
+	t.SetError(err)
+	if t.Callback.OnError != nil {
+		t.Callback.OnError(t)
+	}
+	logrus.Error(err)
+}
+
+func (t *RestoreSnapshotFilesTask) copyFile(source string, target string, info os.FileInfo) error {
+	if _, err := os.Lstat(target); err == nil {
+		if !t.option.Overwrite {
+			t.Extra.Skipped += 1
+			return nil
+		}
+		if err = os.Remove(target); err != nil {
+			return err
+		}
+	}
+	t.Extra.Current = target
+	if info.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(source)
+		if err != nil {
+			return err
+		}
+		if err = os.Symlink(link, target); err != nil {
+			return err
+		}
+		t.Extra.Restored += 1
+		return nil
+	}
+	if !info.Mode().IsRegular() {
+		t.Extra.Skipped += 1
+		return nil
+	}
+	src, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	t.Extra.Restored += 1
+	return os.Chtimes(target, info.ModTime(), info.ModTime())
+}
+
+func (t *RestoreSnapshotFilesTask) restore(file string) error {
+	source, err := t.location.Resolve(file)
+	if err != nil {
+		return err
+	}
+	relativePath, _ := filepath.Rel(t.location.Root, source)
+	targetRoot := t.Extra.Target
+	if len(t.option.Target) > 0 {
+		// restore selected item into target directory keeping only its name
+		relativePath = filepath.Base(relativePath)
+	}
+	return filepath.Walk(source, func(walkPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		subPath, _ := filepath.Rel(source, walkPath)
+		target := filepath.Join(targetRoot, relativePath, subPath)
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		if err = os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		return t.copyFile(walkPath, target, info)
+	})
+}
+
+// resolveRestoreTarget resolve symlinks of target directory, it must be under root of storage or share folder
+func resolveRestoreTarget(target string) (string, error) {
+	if !filepath.IsAbs(target) {
+		return "", InvalidRestoreTargetError
+	}
+	realTarget, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", InvalidRestoreTargetError, err.Error())
+	}
+	info, err := os.Stat(realTarget)
+	if err != nil || !info.IsDir() {
+		return "", InvalidRestoreTargetError
+	}
+	roots := make([]string, 0)
+	for _, storage := range DefaultStoragePool.List() {
+		roots = append(roots, storage.GetRootPath())
+	}
+	folders, err := GetShareFolders()
+	if err != nil {
+		return "", err
+	}
+	for _, folder := range folders {
+		roots = append(roots, folder.Path)
+	}
+	for _, root := range roots {
+		if len(root) == 0 {
+			continue
+		}
+		realRoot, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			continue
+		}
+		if isPathUnder(realTarget, realRoot) {
+			return realTarget, nil
+		}
+	}
+	return "", InvalidRestoreTargetError
+}
+
+// NewRestoreSnapshotFilesTask copy files from snapshot back to original location or target directory
+func (p *TaskPool) NewRestoreSnapshotFilesTask(option RestoreSnapshotFilesOption, callback RestoreSnapshotFilesCallback) (Task, error) {
+	if len(option.Files) == 0 {
+		return nil, errors.New("no file to restore")
+	}
+	location, err := DefaultZFSManager.LocateSnapshotPath(option.Path, option.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range option.Files {
+		if _, err = location.Resolve(file); err != nil {
+			return nil, err
+		}
+	}
+	target := location.Path
+	if len(option.Target) > 0 {
+		if target, err = resolveRestoreTarget(option.Target); err != nil {
+			return nil, err
+		}
+		if isPathUnder(target, filepath.Join(location.MountPoint, ".zfs")) {
+			return nil, errors.New("can not restore into snapshot")
+		}
+	}
+	task := RestoreSnapshotFilesTask{
+		BaseTask: NewBaseTask(),
+		Extra: RestoreSnapshotFilesExtra{
+			Dataset:  location.Dataset,
+			Snapshot: option.Snapshot,
+			Target:   target,
+		},
+		Callback: callback,
+		option:   option,
+		location: location,
+	}
+	go func() {
+		for _, file := range option.Files {
+			if err := task.restore(file); err != nil {
+				task.OnError(err)
+				return
+			}
+		}
+		task.Extra.Current = ""
+		task.SetStatus(TaskStatusDone)
+		if task.Callback.OnDone != nil {
+			task.Callback.OnDone(&task)
+		}
+	}()
+	p.Lock()
+	p.Tasks = append(p.Tasks, &task)
+	p.Unlock()
+	return &task, nil
+}
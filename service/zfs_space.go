@@ -0,0 +1,68 @@
+package service
+
+import (
+	"strconv"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
+)
+
+// DatasetSpace is space accounting of dataset, sizes are in bytes
+type DatasetSpace struct {
+	Used                 uint64 `json:"used"`
+	Available            uint64 `json:"available"`
+	Referenced           uint64 `json:"referenced"`
+	UsedBySnapshots      uint64 `json:"usedBySnapshots"`
+	UsedByChildren       uint64 `json:"usedByChildren"`
+	UsedByDataset        uint64 `json:"usedByDataset"`
+	UsedByRefReservation uint64 `json:"usedByRefReservation"`
+	CompressRatio        string `json:"compressRatio"`
+}
+
+func NewDatasetSpace(dataset *libzfs.Dataset) DatasetSpace {
+	value := func(prop libzfs.Prop) uint64 {
+		number, _ := strconv.ParseUint(dataset.Properties[prop].Value, 10, 64)
+		return number
+	}
+	return DatasetSpace{
+		Used:                 value(libzfs.DatasetPropUsed),
+		Available:            value(libzfs.DatasetPropAvailable),
+		Referenced:           value(libzfs.DatasetPropReferenced),
+		UsedBySnapshots:      value(libzfs.DatasetPropUsedsnap),
+		UsedByChildren:       value(libzfs.DatasetPropUsedchild),
+		UsedByDataset:        value(libzfs.DatasetPropUsedds),
+		UsedByRefReservation: value(libzfs.DatasetPropUsedrefreserv),
+		CompressRatio:        dataset.Properties[libzfs.DatasetPropCompressratio].Value,
+	}
+}
+
+func (m *ZFSManager) GetDatasetSpace(datasetPath string) (*DatasetSpace, error) {
+	dataset, err := libzfs.DatasetOpenSingle(datasetPath)
+	if err != nil {
+		return nil, err
+	}
+	defer dataset.Close()
+	space := NewDatasetSpace(&dataset)
+	return &space, nil
+}
+
+type SnapshotSpace struct {
+	Name       string `json:"name"`
+	Used       uint64 `json:"used"`
+	Referenced uint64 `json:"referenced"`
+}
+
+// GetSnapshotsSpace return space held by each snapshot of dataset
+func (m *ZFSManager) GetSnapshotsSpace(datasetPath string) ([]SnapshotSpace, error) {
+	snapshots, err := m.GetDatasetSnapshotList(datasetPath)
+	if err != nil {
+		return nil, err
+	}
+	defer m.CloseAllDataset(snapshots)
+	result := make([]SnapshotSpace, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		name, _ := snapshot.Path()
+		space := NewDatasetSpace(&snapshot)
+		result = append(result, SnapshotSpace{Name: name, Used: space.Used, Referenced: space.Referenced})
+	}
+	return result, nil
+}
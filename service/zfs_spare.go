@@ -0,0 +1,330 @@
+package service
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
+	"github.com/projectxpolaris/youplus/database"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	HotSpareActionSpare   = "spare"
+	HotSpareActionReplace = "replace"
+)
+
+var SpareLogger = logrus.New().WithFields(logrus.Fields{
+	"scope": "HotSpare",
+})
+
+func GetSparePolicy(poolName string) (*database.SparePolicy, error) {
+	var policy database.SparePolicy
+	err := database.Instance.Where("pool = ?", poolName).First(&policy).Error
+	if err == gorm.ErrRecordNotFound {
+		return &database.SparePolicy{Pool: poolName}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func SetSparePolicy(poolName string, autoSpare bool, autoReplace bool) (*database.SparePolicy, error) {
+	pool, err := DefaultZFSManager.openPool(poolName)
+	if err != nil {
+		return nil, err
+	}
+	pool.Close()
+	policy, err := GetSparePolicy(poolName)
+	if err != nil {
+		return nil, err
+	}
+	policy.AutoSpare = autoSpare
+	policy.AutoReplace = autoReplace
+	if err = database.Instance.Save(policy).Error; err != nil {
+		return nil, err
+	}
+	go DefaultSparePolicyEngine.Check(poolName)
+	return policy, nil
+}
+
+type HotSpareExtra struct {
+	Pool   string `json:"pool"`
+	Device string `json:"device"`
+	// NewDevice is spare or new disk in the slot of failed device
+	NewDevice string  `json:"newDevice"`
+	Action    string  `json:"action"`
+	Progress  float64 `json:"progress"`
+}
+
+type HotSpareCallback struct {
+	OnStart func(task *HotSpareTask)
+	OnDone  func(task *HotSpareTask)
+	OnError func(task *HotSpareTask)
+}
+
+type HotSpareTask struct {
+	BaseTask
+	Extra    HotSpareExtra
+	Callback HotSpareCallback
+}
+
+func (t *HotSpareTask) OnError(err error) {
+	t.SetError(err)
+	if t.Callback.OnError != nil {
+		t.Callback.OnError(t)
+	}
+	SpareLogger.Error(err)
+}
+
+// NewHotSpareTask replace failed device with spare or new disk, then wait for resilver to finish
+func (p *TaskPool) NewHotSpareTask(extra HotSpareExtra, callback HotSpareCallback, onFinish func()) Task {
+	task := HotSpareTask{
+		BaseTask: NewBaseTask(),
+		Extra:    extra,
+		Callback: callback,
+	}
+	go func() {
+		defer onFinish()
+		if task.Callback.OnStart != nil {
+			task.Callback.OnStart(&task)
+		}
+		before, err := DefaultZFSManager.GetPoolScanStatus(extra.Pool)
+		if err != nil {
+			task.OnError(err)
+			return
+		}
+		// spare is configured for pool, new disk must be blank so never force replace onto it
+		if _, err = runZpool("replace", extra.Pool, extra.Device, diskDevicePath(extra.NewDevice)); err != nil {
+			task.OnError(err)
+			return
+		}
+		SpareLogger.WithFields(logrus.Fields{
+			"pool":   extra.Pool,
+			"device": extra.Device,
+			"new":    extra.NewDevice,
+		}).Info("resilver started")
+		for {
+			<-time.After(5 * time.Second)
+			status, err := DefaultZFSManager.GetPoolScanStatus(extra.Pool)
+			if err != nil {
+				task.OnError(err)
+				return
+			}
+			// first poll may land before resilver starts, wait for resilver started after replace
+			if status.Func != "resilver" || status.StartTime <= before.StartTime {
+				continue
+			}
+			task.Extra.Progress = status.Progress
+			task.Updated = time.Now()
+			if status.State != "scanning" {
+				break
+			}
+		}
+		task.Extra.Progress = 1
+		task.SetStatus(TaskStatusDone)
+		if task.Callback.OnDone != nil {
+			task.Callback.OnDone(&task)
+		}
+	}()
+	p.Lock()
+	p.Tasks = append(p.Tasks, &task)
+	p.Unlock()
+	return &task
+}
+
+// SparePolicyEngine watch failed devices and activate spares or replace them according to pool policy
+type SparePolicyEngine struct {
+	Callback HotSpareCallback
+	active   map[string]bool
+	sync.Mutex
+}
+
+var DefaultSparePolicyEngine = SparePolicyEngine{
+	active: map[string]bool{},
+}
+
+// SetCallback set callback of hot spare tasks started by engine
+func (e *SparePolicyEngine) SetCallback(callback HotSpareCallback) {
+	e.Lock()
+	defer e.Unlock()
+	e.Callback = callback
+}
+
+func (e *SparePolicyEngine) Run() {
+	DefaultZFSEventWatcher.AddListener(func(event *database.ZFSEvent) {
+		if event.Level != EventLevelInfo && len(event.Pool) > 0 {
+			go e.Check(event.Pool)
+		}
+	})
+	go func() {
+		for {
+			<-time.After(time.Minute)
+			var policies []database.SparePolicy
+			if err := database.Instance.Find(&policies).Error; err != nil {
+				SpareLogger.Error(err)
+				continue
+			}
+			for _, policy := range policies {
+				if policy.AutoSpare || policy.AutoReplace {
+					e.Check(policy.Pool)
+				}
+			}
+		}
+	}()
+}
+
+type failedDevice struct {
+	Name   string
+	Path   string
+	Spared bool
+}
+
+var partSuffixPattern = regexp.MustCompile(`-part\d+$`)
+
+// vdevPhysPaths read physical path of leaf vdevs from pool config, keyed by vdev path
+func vdevPhysPaths(poolName string) (map[string]string, error) {
+	out, err := exec.Command("zdb", "-C", poolName).Output()
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	path := ""
+	for _, line := range strings.Split(string(out), "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), "'")
+		switch key {
+		case "path":
+			path = value
+		case "phys_path":
+			if len(path) > 0 {
+				result[path] = value
+			}
+		}
+	}
+	return result, nil
+}
+
+// slotDiskName return name of disk currently in the physical slot of failed device, empty when slot is empty
+func slotDiskName(devicePath string, physPath string) string {
+	if len(physPath) == 0 && strings.HasPrefix(devicePath, "/dev/disk/by-path/") {
+		physPath = filepath.Base(devicePath)
+	}
+	if len(physPath) == 0 || strings.Contains(physPath, "/") {
+		return ""
+	}
+	link := filepath.Join("/dev/disk/by-path", partSuffixPattern.ReplaceAllString(physPath, ""))
+	realPath, err := filepath.EvalSymlinks(link)
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(realPath)
+}
+
+// isBlankDisk check disk has no partition table or filesystem signature
+func isBlankDisk(name string) bool {
+	out, err := exec.Command("wipefs", "--noheadings", "--output", "TYPE", diskDevicePath(name)).Output()
+	if err != nil {
+		return false
+	}
+	return len(strings.TrimSpace(string(out))) == 0
+}
+
+func isFailedVdev(vt libzfs.VDevTree) bool {
+	switch vt.Stat.State {
+	case libzfs.VDevStateFaulted, libzfs.VDevStateRemoved, libzfs.VDevStateCantOpen:
+		return true
+	}
+	return false
+}
+
+// collectFailedDevices walk vdev tree for failed leaf devices, devices already under spare or replacing are marked spared
+func collectFailedDevices(vt libzfs.VDevTree, spared bool, result *[]failedDevice) {
+	if len(vt.Devices) == 0 && vt.Type != libzfs.VDevTypeRoot {
+		if isFailedVdev(vt) {
+			name := vt.Path
+			if len(name) == 0 {
+				name = strconv.FormatUint(vt.GUID, 10)
+			}
+			*result = append(*result, failedDevice{Name: name, Path: vt.Path, Spared: spared})
+		}
+		return
+	}
+	childSpared := spared || vt.Type == libzfs.VDevTypeSpare || vt.Type == libzfs.VDevTypeReplacing
+	for _, child := range vt.Devices {
+		collectFailedDevices(child, childSpared, result)
+	}
+}
+
+func (e *SparePolicyEngine) Check(poolName string) {
+	policy, err := GetSparePolicy(poolName)
+	if err != nil || (!policy.AutoSpare && !policy.AutoReplace) {
+		return
+	}
+	pool, err := DefaultZFSManager.openPool(poolName)
+	if err != nil {
+		return
+	}
+	vt, err := pool.VDevTree()
+	pool.Close()
+	if err != nil {
+		SpareLogger.Error(err)
+		return
+	}
+	failed := make([]failedDevice, 0)
+	collectFailedDevices(vt, false, &failed)
+	physPaths := map[string]string{}
+	if policy.AutoReplace && len(failed) > 0 {
+		if physPaths, err = vdevPhysPaths(poolName); err != nil {
+			SpareLogger.Error(err)
+		}
+	}
+	spares := make([]string, 0)
+	for _, spare := range vt.Spares {
+		if spare.Stat.State == libzfs.VDevStateHealthy && spare.Stat.Aux != libzfs.VDevAuxSpared {
+			spares = append(spares, spare.Path)
+		}
+	}
+	e.Lock()
+	defer e.Unlock()
+	for _, device := range failed {
+		key := fmt.Sprintf("%s/%s", poolName, device.Name)
+		if e.active[key] {
+			continue
+		}
+		extra := HotSpareExtra{Pool: poolName, Device: device.Name}
+		slotDisk := ""
+		if policy.AutoReplace && len(device.Path) > 0 {
+			slotDisk = slotDiskName(device.Path, physPaths[device.Path])
+		}
+		switch {
+		case len(slotDisk) > 0 && CheckDiskAvailable(slotDisk) == nil && isBlankDisk(slotDisk):
+			// new blank disk shows up in the physical slot of failed device
+			extra.Action = HotSpareActionReplace
+			extra.NewDevice = slotDisk
+		case policy.AutoSpare && !device.Spared && len(spares) > 0:
+			extra.Action = HotSpareActionSpare
+			extra.NewDevice = spares[0]
+			spares = spares[1:]
+		default:
+			continue
+		}
+		e.active[key] = true
+		DefaultTaskPool.NewHotSpareTask(extra, e.Callback, func() {
+			e.Lock()
+			delete(e.active, key)
+			e.Unlock()
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/rs/xid"
 	"github.com/spf13/afero"
 	"path"
+	"strings"
 )
 
 type ZFSPoolStorage struct {
@@ -58,21 +59,13 @@ func (z *ZFSPoolStorage) Remove() error {
 	return database.Instance.Model(&database.ZFSStorage{}).Unscoped().Delete(&database.ZFSStorage{ID: z.Id}).Error
 }
 
+// GetUsage return space used by dataset of storage and its total size, which is used plus available
 func (z *ZFSPoolStorage) GetUsage() (used int64, free int64, err error) {
-	dataset, err := libzfs.DatasetOpen(z.MountPoint)
+	space, err := DefaultZFSManager.GetDatasetSpace(z.MountPoint)
 	if err != nil {
 		return 0, 0, err
 	}
-	pool, err := dataset.Pool()
-	if err != nil {
-		return 0, 0, err
-	}
-	vtree, err := pool.VDevTree()
-	if err != nil {
-		return 0, 0, err
-	}
-	return int64(vtree.Stat.Alloc), int64(vtree.Stat.Space), nil
-
+	return int64(space.Used), int64(space.Used + space.Available), nil
 }
 
 func (z *ZFSPoolStorage) LoadFromSave(data *database.ZFSStorage) error {
@@ -104,7 +97,9 @@ func CreateZFSStorage(datasetPath string) (Storage, error) {
 		Id:         xid.New().String(),
 		Name:       path.Base(datasetPath),
 		MountPoint: datasetPath,
+		PoolName:   strings.Split(datasetPath, "/")[0],
 	}
+	s.Fs = afero.NewBasePathFs(afero.NewOsFs(), s.GetRootPath())
 	err := database.Instance.Save(&database.ZFSStorage{ID: s.Id, Name: s.Name, MountPoint: s.MountPoint}).Error
 	if err != nil {
 		return nil, err
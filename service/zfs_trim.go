@@ -0,0 +1,132 @@
+package service
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
+)
+
+const (
+	TrimStateUntrimmed   = "untrimmed"
+	TrimStateUnsupported = "unsupported"
+	TrimStateTrimming    = "trimming"
+	TrimStateSuspended   = "suspended"
+	TrimStateCompleted   = "completed"
+)
+
+type DeviceTrimState struct {
+	Device   string  `json:"device"`
+	State    string  `json:"state"`
+	Progress float64 `json:"progress"`
+	// Time is when trim started, suspended or completed as zpool prints it
+	Time string `json:"time,omitempty"`
+}
+
+type PoolTrimStatus struct {
+	Pool     string            `json:"pool"`
+	Autotrim bool              `json:"autotrim"`
+	Devices  []DeviceTrimState `json:"devices"`
+}
+
+var trimProgressPattern = regexp.MustCompile(`\((\d+)% trimmed, (\w+) at (.+)\)$`)
+
+// StartTrim start manual trim of all or given leaf devices of pool
+func (m *ZFSManager) StartTrim(poolName string, devices []string) error {
+	pool, err := m.openPool(poolName)
+	if err != nil {
+		return err
+	}
+	pool.Close()
+	args := append([]string{"trim", poolName}, devices...)
+	_, err = runZpool(args...)
+	return err
+}
+
+// CancelTrim cancel running trim of all or given leaf devices of pool
+func (m *ZFSManager) CancelTrim(poolName string, devices []string) error {
+	pool, err := m.openPool(poolName)
+	if err != nil {
+		return err
+	}
+	pool.Close()
+	args := append([]string{"trim", "-c", poolName}, devices...)
+	_, err = runZpool(args...)
+	return err
+}
+
+func (m *ZFSManager) SetAutotrim(poolName string, enable bool) error {
+	pool, err := m.openPool(poolName)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	value := "off"
+	if enable {
+		value = "on"
+	}
+	return pool.SetProperty(libzfs.PoolPropAutotrim, value)
+}
+
+// GetTrimStatus read autotrim property and per device trim state from zpool status -t
+func (m *ZFSManager) GetTrimStatus(poolName string) (*PoolTrimStatus, error) {
+	pool, err := m.openPool(poolName)
+	if err != nil {
+		return nil, err
+	}
+	status := &PoolTrimStatus{
+		Pool:     poolName,
+		Autotrim: pool.Properties[libzfs.PoolPropAutotrim].Value == "on",
+	}
+	pool.Close()
+	output, err := runZpool("status", "-t", poolName)
+	if err != nil {
+		return nil, err
+	}
+	status.Devices = parseTrimStatus(output)
+	return status, nil
+}
+
+func parseTrimStatus(output string) []DeviceTrimState {
+	devices := make([]DeviceTrimState, 0)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasSuffix(line, ")") {
+			continue
+		}
+		state := DeviceTrimState{Device: fields[0]}
+		switch {
+		case strings.HasSuffix(line, "(untrimmed)"):
+			state.State = TrimStateUntrimmed
+		case strings.HasSuffix(line, "(trim unsupported)"):
+			state.State = TrimStateUnsupported
+		default:
+			match := trimProgressPattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			percent, _ := strconv.Atoi(match[1])
+			state.Progress = float64(percent) / 100
+			state.Time = match[3]
+			switch match[2] {
+			case "completed":
+				state.State = TrimStateCompleted
+			case "suspended":
+				state.State = TrimStateSuspended
+			default:
+				state.State = TrimStateTrimming
+			}
+		}
+		devices = append(devices, state)
+	}
+	return devices
+}
+
+// runTrimJob is scheduled job runner of JobTypeTrim, target is pool name
+func runTrimJob(target string) error {
+	return DefaultZFSManager.StartTrim(target, nil)
+}
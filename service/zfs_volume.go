@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	libzfs "github.com/bicomsystems/go-libzfs"
+	"github.com/projectxpolaris/youplus/utils"
+)
+
+var (
+	VolumeInUseError       = errors.New("volume is used by storage")
+	UnsupportedFormatError = errors.New("unsupported filesystem format")
+)
+
+// VolumeFormatMapping is filesystems a volume can be formatted with after creation
+var VolumeFormatMapping = map[string]string{
+	"ext4": "mkfs.ext4",
+	"xfs":  "mkfs.xfs",
+}
+
+type CreateVolumeOption struct {
+	Path      string            `json:"path"`
+	Size      string            `json:"size"`
+	BlockSize string            `json:"blockSize"`
+	Sparse    bool              `json:"sparse"`
+	Props     map[string]string `json:"props"`
+	Format    string            `json:"format"`
+}
+
+// GetVolumeDevicePath return block device path of zvol
+func GetVolumeDevicePath(datasetPath string) string {
+	return filepath.Join("/dev/zvol", datasetPath)
+}
+
+func waitForDevice(devicePath string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if _, err := os.Stat(devicePath); err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("device %s not ready", devicePath)
+		}
+		<-time.After(200 * time.Millisecond)
+	}
+}
+
+func (m *ZFSManager) CreateVolume(option CreateVolumeOption) (dataset libzfs.Dataset, err error) {
+	if _, err = ParseZFSSize(option.Size); err != nil {
+		return dataset, err
+	}
+	args := []string{"create"}
+	if option.Sparse {
+		args = append(args, "-s")
+	}
+	args = append(args, "-V", option.Size)
+	if len(option.BlockSize) > 0 {
+		if err = validateRecordSize(option.BlockSize); err != nil {
+			return dataset, err
+		}
+		args = append(args, "-b", option.BlockSize)
+	}
+	for name, value := range option.Props {
+		if _, err = ValidateDatasetProperty(name, value); err != nil {
+			return dataset, err
+		}
+		args = append(args, "-o", fmt.Sprintf("%s=%s", name, value))
+	}
+	var mkfs string
+	if len(option.Format) > 0 {
+		var ok bool
+		if mkfs, ok = VolumeFormatMapping[option.Format]; !ok {
+			return dataset, fmt.Errorf("%w: %s", UnsupportedFormatError, option.Format)
+		}
+	}
+	args = append(args, option.Path)
+	if _, err = runZfs(args...); err != nil {
+		return dataset, err
+	}
+	if len(mkfs) > 0 {
+		devicePath := GetVolumeDevicePath(option.Path)
+		if err = waitForDevice(devicePath, 10*time.Second); err != nil {
+			return dataset, err
+		}
+		out, err := exec.Command(mkfs, devicePath).CombinedOutput()
+		if err != nil {
+			return dataset, fmt.Errorf("format %s failed: %s", devicePath, strings.TrimSpace(string(out)))
+		}
+	}
+	return libzfs.DatasetOpen(option.Path)
+}
+
+// ResizeVolume change volsize of zvol, shrinking is only allowed with force and never when zvol backs storage.
+// ext4 or xfs filesystem of storage on zvol is grown together, return whether filesystem was resized
+func (m *ZFSManager) ResizeVolume(datasetPath string, size string, force bool) (bool, error) {
+	newSize, err := ParseZFSSize(size)
+	if err != nil {
+		return false, err
+	}
+	dataset, err := libzfs.DatasetOpenSingle(datasetPath)
+	if err != nil {
+		return false, err
+	}
+	defer dataset.Close()
+	if dataset.Type != libzfs.DatasetTypeVolume {
+		return false, fmt.Errorf("%s is not a volume", datasetPath)
+	}
+	currentSize, err := dataset.GetProperty(libzfs.DatasetPropVolsize)
+	if err != nil {
+		return false, err
+	}
+	current, err := ParseZFSSize(currentSize.Value)
+	if err != nil {
+		return false, err
+	}
+	storage, _ := GetVolumeStorage(datasetPath).(*DiskPartStorage)
+	if newSize < current {
+		if storage != nil {
+			return false, fmt.Errorf("%w: mounted filesystem can not be shrunk", VolumeInUseError)
+		}
+		if !force {
+			return false, errors.New("shrinking volume may destroy data, use force to continue")
+		}
+	}
+	if _, err = runZfs("set", fmt.Sprintf("volsize=%s", size), datasetPath); err != nil {
+		return false, err
+	}
+	if storage == nil || newSize <= current {
+		return false, nil
+	}
+	devicePath := GetVolumeDevicePath(datasetPath)
+	var out []byte
+	switch utils.Lsblk()[ResolveDeviceName(devicePath)]["fstype"] {
+	case "ext4":
+		out, err = exec.Command("resize2fs", devicePath).CombinedOutput()
+	case "xfs":
+		out, err = exec.Command("xfs_growfs", storage.MountPoint).CombinedOutput()
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("volume resized but filesystem grow failed: %s", strings.TrimSpace(string(out)))
+	}
+	return true, nil
+}
+
+// GetVolumeStorage return storage which use zvol as source
+func GetVolumeStorage(datasetPath string) Storage {
+	devicePath := GetVolumeDevicePath(datasetPath)
+	realPath, err := filepath.EvalSymlinks(devicePath)
+	if err != nil {
+		realPath = devicePath
+	}
+	for _, storage := range DefaultStoragePool.List() {
+		partStorage, ok := storage.(*DiskPartStorage)
+		if !ok {
+			continue
+		}
+		// source is stable by-uuid or by-id path, compare kernel names of both
+		if ResolveDeviceName(partStorage.Source) == filepath.Base(realPath) {
+			return storage
+		}
+	}
+	return nil
+}
+
+func (m *ZFSManager) DeleteVolume(datasetPath string) error {
+	if GetVolumeStorage(datasetPath) != nil {
+		return VolumeInUseError
+	}
+	return m.DeleteDataset(datasetPath)
+}
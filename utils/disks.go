@@ -57,7 +57,10 @@ func ParserLsblk(r io.Reader) map[string]map[string]string {
 		sr := re.FindAllStringSubmatch(raw, -1)
 		for i, k := range sr {
 			k[1] = strings.ToLower(k[1])
-			k[2] = strings.ToLower(k[2])
+			// serial and filesystem uuid are case sensitive identifiers
+			if k[1] != "serial" && k[1] != "uuid" {
+				k[2] = strings.ToLower(k[2])
+			}
 			if i == 0 {
 				disk_name = k[2]
 			}
@@ -84,7 +87,7 @@ func ParserLsblk(r io.Reader) map[string]map[string]string {
 
 func Lsblk() Disks {
 	var cmdrun = CmdRunner{}
-	rr, err := cmdrun.Run("lsblk", []string{"-P", "-b", "-o", "NAME,KNAME,MODEL,PARTUUID,SIZE,ROTA,TYPE,MOUNTPOINT,MAJ:MIN,PKNAME,FSTYPE"})
+	rr, err := cmdrun.Run("lsblk", []string{"-P", "-b", "-o", "NAME,KNAME,MODEL,PARTUUID,SIZE,ROTA,TYPE,MOUNTPOINT,MAJ:MIN,PKNAME,FSTYPE,SERIAL,WWN,UUID"})
 	if err != nil {
 		fmt.Println(err)
 	}
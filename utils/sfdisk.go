@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
 	"os/exec"
+	"strings"
 )
 
 func WipeDiskFS(device string) error {
@@ -31,3 +33,51 @@ func DeletePartition(device string, partitionId int) error {
 	}
 	return nil
 }
+
+type SfdiskPartition struct {
+	Node  string `json:"node"`
+	Start uint64 `json:"start"`
+	Size  uint64 `json:"size"`
+	Type  string `json:"type"`
+	UUID  string `json:"uuid"`
+	Name  string `json:"name"`
+}
+
+type SfdiskPartitionTable struct {
+	Label      string            `json:"label"`
+	Id         string            `json:"id"`
+	Device     string            `json:"device"`
+	FirstLBA   uint64            `json:"firstlba"`
+	LastLBA    uint64            `json:"lastlba"`
+	SectorSize uint64            `json:"sectorsize"`
+	Partitions []SfdiskPartition `json:"partitions"`
+}
+
+// ReadPartitionTable dump partition table of device, positions are in sectors
+func ReadPartitionTable(device string) (*SfdiskPartitionTable, error) {
+	out, err := exec.Command("sfdisk", "--json", device).Output()
+	if err != nil {
+		return nil, err
+	}
+	var dump struct {
+		PartitionTable SfdiskPartitionTable `json:"partitiontable"`
+	}
+	if err = json.Unmarshal(out, &dump); err != nil {
+		return nil, err
+	}
+	if dump.PartitionTable.SectorSize == 0 {
+		dump.PartitionTable.SectorSize = 512
+	}
+	return &dump.PartitionTable, nil
+}
+
+// RunSfdiskScript feed script to sfdisk, output is returned for task log
+func RunSfdiskScript(script string, args ...string) (string, error) {
+	cmd := exec.Command("sfdisk", args...)
+	cmd.Stdin = strings.NewReader(script)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("sfdisk failed: %s", strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}